@@ -0,0 +1,10 @@
+package objc
+
+// dispatchKind tells which objc_msgSend entry point a return type needs.
+type dispatchKind int
+
+const (
+	dispatchNormal dispatchKind = iota
+	dispatchStret
+	dispatchFpret
+)
@@ -0,0 +1,26 @@
+package objc
+
+import (
+	"runtime"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// On i386, floating-point values are returned on the x87 stack. Darwin
+// returns structs of 1, 2, 4 and 8 bytes in registers, other systems return
+// every struct in memory.
+func returnDispatch(t *encoding.Type, size uintptr) dispatchKind {
+	switch t.Kind {
+	case encoding.Struct:
+		if runtime.GOOS == "darwin" && (size == 1 || size == 2 || size == 4 || size == 8) {
+			return dispatchNormal
+		}
+
+		return dispatchStret
+
+	case encoding.Float, encoding.Double, encoding.LongDouble:
+		return dispatchFpret
+	}
+
+	return dispatchNormal
+}
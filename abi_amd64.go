@@ -0,0 +1,17 @@
+package objc
+
+import "github.com/maxence-charriere/go-objcruntime/encoding"
+
+// On x86_64, structs larger than 16 bytes are returned in memory and long
+// double is returned on the x87 stack.
+func returnDispatch(t *encoding.Type, size uintptr) dispatchKind {
+	switch {
+	case t.Kind == encoding.Struct && size > 16:
+		return dispatchStret
+
+	case t.Kind == encoding.LongDouble:
+		return dispatchFpret
+	}
+
+	return dispatchNormal
+}
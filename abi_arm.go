@@ -0,0 +1,12 @@
+package objc
+
+import "github.com/maxence-charriere/go-objcruntime/encoding"
+
+// On ARM, structs larger than 4 bytes are returned in memory.
+func returnDispatch(t *encoding.Type, size uintptr) dispatchKind {
+	if t.Kind == encoding.Struct && size > 4 {
+		return dispatchStret
+	}
+
+	return dispatchNormal
+}
@@ -0,0 +1,11 @@
+//go:build !386 && !amd64 && !arm
+
+package objc
+
+import "github.com/maxence-charriere/go-objcruntime/encoding"
+
+// On arm64 and the remaining architectures, objc_msgSend handles every
+// return type.
+func returnDispatch(t *encoding.Type, size uintptr) dispatchKind {
+	return dispatchNormal
+}
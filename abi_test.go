@@ -0,0 +1,31 @@
+package objc
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestReturnDispatch(t *testing.T) {
+	tests := []struct {
+		types    string
+		expected map[string]dispatchKind
+	}{
+		{"v", map[string]dispatchKind{}},
+		{"@", map[string]dispatchKind{}},
+		{"d", map[string]dispatchKind{"386": dispatchFpret}},
+		{"{CGPoint=ff}", map[string]dispatchKind{"arm": dispatchStret}},
+		{"{CGRect={CGPoint=dd}{CGSize=dd}}", map[string]dispatchKind{"386": dispatchStret, "amd64": dispatchStret, "arm": dispatchStret}},
+	}
+
+	for _, test := range tests {
+		sig, err := newSignature(test.types + "@:")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := test.expected[runtime.GOARCH]; sig.dispatch != expected {
+			t.Errorf("%s dispatch should be %d on %s: %d", test.types, expected, runtime.GOARCH, sig.dispatch)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package objc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// PropertyAttributes are the typed attributes of a property, as described
+// by Property_getAttributes.
+type PropertyAttributes struct {
+	Type      string
+	ReadOnly  bool
+	Copy      bool
+	Retain    bool
+	Weak      bool
+	Nonatomic bool
+	Dynamic   bool
+	Getter    string
+	Setter    string
+	Ivar      string
+
+	// Other holds the attributes without a typed field, such as the
+	// garbage collection attribute P.
+	Other []PropertyAttribute
+}
+
+// ParsePropertyAttributes parses an attribute string such as
+// `T@"NSString",&,N,V_name`. Attributes are separated by commas, so type
+// encodings containing commas are not supported.
+func ParsePropertyAttributes(attributes string) (PropertyAttributes, error) {
+	var list []PropertyAttribute
+
+	if attributes != "" {
+		for _, attr := range strings.Split(attributes, ",") {
+			if attr == "" {
+				return PropertyAttributes{}, fmt.Errorf("objc: bad property attributes %q: empty attribute", attributes)
+			}
+
+			list = append(list, PropertyAttribute{Name: attr[:1], Value: attr[1:]})
+		}
+	}
+
+	return MakePropertyAttributes(list)
+}
+
+// MakePropertyAttributes returns the typed attributes from name/value
+// pairs, as returned by Property_copyAttributeList.
+func MakePropertyAttributes(list []PropertyAttribute) (attrs PropertyAttributes, err error) {
+	for _, attr := range list {
+		switch attr.Name {
+		case "T":
+			if _, err = encoding.Parse(attr.Value); err != nil {
+				return PropertyAttributes{}, fmt.Errorf("objc: bad property type: %v", err)
+			}
+
+			attrs.Type = attr.Value
+
+		case "R":
+			attrs.ReadOnly = true
+
+		case "C":
+			attrs.Copy = true
+
+		case "&":
+			attrs.Retain = true
+
+		case "W":
+			attrs.Weak = true
+
+		case "N":
+			attrs.Nonatomic = true
+
+		case "D":
+			attrs.Dynamic = true
+
+		case "G":
+			attrs.Getter = attr.Value
+
+		case "S":
+			attrs.Setter = attr.Value
+
+		case "V":
+			attrs.Ivar = attr.Value
+
+		default:
+			attrs.Other = append(attrs.Other, attr)
+		}
+	}
+
+	return attrs, nil
+}
+
+// List returns the attributes as name/value pairs, as expected by
+// Class_addProperty and Protocol_addProperty. The type comes first and
+// the instance variable last.
+func (attrs PropertyAttributes) List() (list []PropertyAttribute) {
+	if attrs.Type != "" {
+		list = append(list, PropertyAttribute{Name: "T", Value: attrs.Type})
+	}
+
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"R", attrs.ReadOnly},
+		{"C", attrs.Copy},
+		{"&", attrs.Retain},
+		{"W", attrs.Weak},
+		{"N", attrs.Nonatomic},
+		{"D", attrs.Dynamic},
+	}
+
+	for _, flag := range flags {
+		if flag.set {
+			list = append(list, PropertyAttribute{Name: flag.name})
+		}
+	}
+
+	if attrs.Getter != "" {
+		list = append(list, PropertyAttribute{Name: "G", Value: attrs.Getter})
+	}
+
+	if attrs.Setter != "" {
+		list = append(list, PropertyAttribute{Name: "S", Value: attrs.Setter})
+	}
+
+	list = append(list, attrs.Other...)
+
+	if attrs.Ivar != "" {
+		list = append(list, PropertyAttribute{Name: "V", Value: attrs.Ivar})
+	}
+
+	return
+}
+
+// String returns the attribute string, in the format of
+// Property_getAttributes.
+func (attrs PropertyAttributes) String() string {
+	list := attrs.List()
+	attributes := make([]string, len(list))
+
+	for i, attr := range list {
+		attributes[i] = attr.Name + attr.Value
+	}
+
+	return strings.Join(attributes, ",")
+}
+
+// Declaration returns the Objective-C declaration of the property name,
+// such as "@property (nonatomic, copy) NSString *name;".
+func (attrs PropertyAttributes) Declaration(name string) (string, error) {
+	t, err := encoding.Parse(attrs.Type)
+
+	if err != nil {
+		return "", fmt.Errorf("objc: bad type of property %s: %v", name, err)
+	}
+
+	var options []string
+
+	if attrs.Nonatomic {
+		options = append(options, "nonatomic")
+	}
+
+	switch {
+	case attrs.Copy:
+		options = append(options, "copy")
+
+	case attrs.Retain:
+		options = append(options, "retain")
+
+	case attrs.Weak:
+		options = append(options, "weak")
+	}
+
+	if attrs.ReadOnly {
+		options = append(options, "readonly")
+	}
+
+	if attrs.Getter != "" {
+		options = append(options, "getter="+attrs.Getter)
+	}
+
+	if attrs.Setter != "" {
+		options = append(options, "setter="+attrs.Setter)
+	}
+
+	decl := "@property "
+
+	if len(options) != 0 {
+		decl += "(" + strings.Join(options, ", ") + ") "
+	}
+
+	return decl + t.Decl(name) + ";", nil
+}
@@ -0,0 +1,103 @@
+package objc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePropertyAttributes(t *testing.T) {
+	tests := []struct {
+		attributes string
+		expected   PropertyAttributes
+	}{
+		{"", PropertyAttributes{}},
+		{"Tc,VcharDefault", PropertyAttributes{Type: "c", Ivar: "charDefault"}},
+		{`T@"NSString",&,N,V_name`, PropertyAttributes{Type: `@"NSString"`, Retain: true, Nonatomic: true, Ivar: "_name"}},
+		{`T@"NSString",R,C`, PropertyAttributes{Type: `@"NSString"`, ReadOnly: true, Copy: true}},
+		{"T@,W,D", PropertyAttributes{Type: "@", Weak: true, Dynamic: true}},
+		{"TB,N,GisEnabled,SsetOn:", PropertyAttributes{Type: "B", Nonatomic: true, Getter: "isEnabled", Setter: "setOn:"}},
+		{"T{CGPoint=dd},P,V_origin", PropertyAttributes{Type: "{CGPoint=dd}", Ivar: "_origin", Other: []PropertyAttribute{{Name: "P"}}}},
+	}
+
+	for _, test := range tests {
+		attrs, err := ParsePropertyAttributes(test.attributes)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.attributes, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(attrs, test.expected) {
+			t.Errorf("%s should be parsed to %+v: %+v", test.attributes, test.expected, attrs)
+		}
+
+		if s := attrs.String(); s != test.attributes {
+			t.Errorf("attributes should be formatted as %s: %s", test.attributes, s)
+		}
+	}
+}
+
+func TestParsePropertyAttributesError(t *testing.T) {
+	for _, attributes := range []string{"Tc,,N", "T{CGPoint=dd", ","} {
+		if _, err := ParsePropertyAttributes(attributes); err == nil {
+			t.Errorf("%q should not be parsed", attributes)
+		}
+	}
+}
+
+func TestPropertyAttributesList(t *testing.T) {
+	attrs := PropertyAttributes{
+		Type:      `@"NSString"`,
+		Copy:      true,
+		Nonatomic: true,
+		Ivar:      "_title",
+	}
+
+	proto := Objc_allocateProtocol("PropertyAttributesListProto")
+	Protocol_addProperty(proto, "title", attrs.List(), true, true)
+	Objc_registerProtocol(proto)
+
+	property := Protocol_getProperty(proto, "title", true, true)
+
+	if attributes := Property_getAttributes(property); attributes != attrs.String() {
+		t.Errorf("attributes should be %s: %s", attrs, attributes)
+	}
+
+	parsed, err := MakePropertyAttributes(Property_copyAttributeList(property))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, attrs) {
+		t.Errorf("attributes should be %+v: %+v", attrs, parsed)
+	}
+}
+
+func TestPropertyAttributesDeclaration(t *testing.T) {
+	tests := []struct {
+		attrs    PropertyAttributes
+		expected string
+	}{
+		{PropertyAttributes{Type: `@"NSString"`, Copy: true, Nonatomic: true}, "@property (nonatomic, copy) NSString *title;"},
+		{PropertyAttributes{Type: "c", ReadOnly: true, Getter: "isTitle"}, "@property (readonly, getter=isTitle) char title;"},
+		{PropertyAttributes{Type: "q"}, "@property long long title;"},
+	}
+
+	for _, test := range tests {
+		decl, err := test.attrs.Declaration("title")
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if decl != test.expected {
+			t.Errorf("declaration should be %s: %s", test.expected, decl)
+		}
+	}
+
+	if _, err := (PropertyAttributes{}).Declaration("title"); err == nil {
+		t.Error("property without type should not be declared")
+	}
+}
@@ -0,0 +1,261 @@
+package objc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BuildError lists every step that failed while building a class or a
+// protocol.
+type BuildError struct {
+	Name   string
+	Errors []error
+}
+
+func (err *BuildError) Error() string {
+	messages := make([]string, len(err.Errors))
+
+	for i, stepErr := range err.Errors {
+		messages[i] = stepErr.Error()
+	}
+
+	return fmt.Sprintf("objc: cannot build %s: %s", err.Name, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed steps.
+func (err *BuildError) Unwrap() []error {
+	return err.Errors
+}
+
+// ClassBuilder collects the definition of a class and registers it at
+// once:
+//
+//	cls, err := NewClassBuilder("Counter", Objc_getClass("NSObject")).
+//		Ivar("count", "q").
+//		Method(Sel_registerName("increment"), func(self Id, cmd Sel) { ... }).
+//		Register()
+type ClassBuilder struct {
+	name       string
+	superclass Class
+	extraBytes uint
+	steps      []func(cls Class) error
+	imps       []Imp
+	protocols  []Protocol
+	verify     bool
+}
+
+// NewClassBuilder returns a builder for the class name, a subclass of
+// superclass. A nil superclass defines a root class.
+func NewClassBuilder(name string, superclass Class) *ClassBuilder {
+	return &ClassBuilder{
+		name:       name,
+		superclass: superclass,
+	}
+}
+
+// ExtraBytes sets the number of bytes allocated at the end of each
+// instance for indexed instance variables.
+func (b *ClassBuilder) ExtraBytes(extraBytes uint) *ClassBuilder {
+	b.extraBytes = extraBytes
+	return b
+}
+
+// Ivar adds an instance variable of the encoded type types.
+func (b *ClassBuilder) Ivar(name string, types string) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		if !Class_addIvarWithType(cls, name, types) {
+			return fmt.Errorf("cannot add ivar %s of type %s", name, types)
+		}
+
+		return nil
+	})
+}
+
+// GoIvar adds an instance variable laid out like the Go type t.
+func (b *ClassBuilder) GoIvar(name string, t reflect.Type) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return Class_addGoIvar(cls, name, t)
+	})
+}
+
+// Method adds an instance method implemented by the Go function fn. Its
+// type encoding is inferred with MethodTypeEncoding.
+func (b *ClassBuilder) Method(name Sel, fn interface{}) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return b.addGoMethod(cls, name, fn, "", false)
+	})
+}
+
+// MethodWithTypes adds an instance method implemented by the Go function
+// fn, which must match the type encoding types.
+func (b *ClassBuilder) MethodWithTypes(name Sel, fn interface{}, types string) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return b.addGoMethod(cls, name, fn, types, false)
+	})
+}
+
+// MethodImp adds an instance method implemented by imp.
+func (b *ClassBuilder) MethodImp(name Sel, imp Imp, types string) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return addMethod(cls, name, imp, types, false)
+	})
+}
+
+// ClassMethod adds a class method implemented by the Go function fn, whose
+// first parameter receives the class as an Id.
+func (b *ClassBuilder) ClassMethod(name Sel, fn interface{}) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return b.addGoMethod(cls, name, fn, "", true)
+	})
+}
+
+// ClassMethodImp adds a class method implemented by imp.
+func (b *ClassBuilder) ClassMethodImp(name Sel, imp Imp, types string) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		return addMethod(cls, name, imp, types, true)
+	})
+}
+
+// Property adds a property along with its instance variable and accessors,
+// as SynthesizeProperty does.
+func (b *ClassBuilder) Property(name string, attrs PropertyAttributes) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		imps, err := synthesizeProperty(cls, name, attrs)
+		b.imps = append(b.imps, imps...)
+		return err
+	})
+}
+
+// DynamicProperty adds the metadata of a property whose accessors are
+// provided separately.
+func (b *ClassBuilder) DynamicProperty(name string, attrs PropertyAttributes) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		if !Class_addProperty(cls, name, attrs.List()) {
+			return fmt.Errorf("cannot add property %s", name)
+		}
+
+		return nil
+	})
+}
+
+// Protocol declares that the class adopts proto.
+func (b *ClassBuilder) Protocol(proto Protocol) *ClassBuilder {
+	return b.step(func(cls Class) error {
+		if proto == nil {
+			return fmt.Errorf("cannot adopt a nil protocol")
+		}
+
+		if !Class_addProtocol(cls, proto) {
+			return fmt.Errorf("cannot adopt %s", Protocol_getName(proto))
+		}
+
+		b.protocols = append(b.protocols, proto)
+		return nil
+	})
+}
+
+// VerifyConformance makes Register check with VerifyConformance that the
+// class conforms to the protocols it adopts before registering it.
+func (b *ClassBuilder) VerifyConformance() *ClassBuilder {
+	b.verify = true
+	return b
+}
+
+// Register allocates the class, runs every step and registers the class.
+// When a step fails, the partially built class is disposed and the
+// returned *BuildError lists every failed step.
+func (b *ClassBuilder) Register() (Class, error) {
+	if b.name == "" {
+		return nil, &BuildError{Errors: []error{fmt.Errorf("missing class name")}}
+	}
+
+	if Objc_getClass(b.name) != nil {
+		return nil, &BuildError{Name: b.name, Errors: []error{fmt.Errorf("class already exists")}}
+	}
+
+	cls := Objc_allocateClassPair(b.superclass, b.name, b.extraBytes)
+
+	if cls == nil {
+		return nil, &BuildError{Name: b.name, Errors: []error{fmt.Errorf("cannot allocate class pair")}}
+	}
+
+	var errs []error
+
+	b.protocols = nil
+
+	for _, step := range b.steps {
+		if err := step(cls); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if b.verify && len(errs) == 0 {
+		for _, proto := range b.protocols {
+			if err := VerifyConformance(cls, proto); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) != 0 {
+		Objc_disposeClassPair(cls)
+
+		for _, imp := range b.imps {
+			FreeImp(imp)
+		}
+
+		b.imps = nil
+		return nil, &BuildError{Name: b.name, Errors: errs}
+	}
+
+	Objc_registerClassPair(cls)
+	return cls, nil
+}
+
+func (b *ClassBuilder) step(step func(cls Class) error) *ClassBuilder {
+	b.steps = append(b.steps, step)
+	return b
+}
+
+// addGoMethod adds a method implemented by fn. Its type encoding is
+// inferred when types is empty.
+func (b *ClassBuilder) addGoMethod(cls Class, name Sel, fn interface{}, types string, isClassMethod bool) error {
+	var err error
+
+	if types == "" {
+		if types, err = MethodTypeEncoding(fn); err != nil {
+			return err
+		}
+	}
+
+	imp, err := NewImp(fn, types)
+
+	if err != nil {
+		return err
+	}
+
+	if err = addMethod(cls, name, imp, types, isClassMethod); err != nil {
+		FreeImp(imp)
+		return err
+	}
+
+	b.imps = append(b.imps, imp)
+	return nil
+}
+
+func addMethod(cls Class, name Sel, imp Imp, types string, isClassMethod bool) error {
+	if isClassMethod {
+		if !Class_addClassMethod(cls, name, imp, types) {
+			return fmt.Errorf("cannot add class method %s", Sel_getName(name))
+		}
+
+		return nil
+	}
+
+	if !Class_addMethod(cls, name, imp, types) {
+		return fmt.Errorf("cannot add method %s", Sel_getName(name))
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package objc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassBuilder(t *testing.T) {
+	proto := Objc_allocateProtocol("ClassBuilderProto")
+	Objc_registerProtocol(proto)
+
+	cls, err := NewClassBuilder("ClassBuiltWithBuilder", Objc_getClass("NSObject")).
+		Ivar("count", "q").
+		GoIvar("weights", reflect.TypeOf([4]float32{})).
+		Method(Sel_registerName("answer"), func(self Id, cmd Sel) int32 { return 42 }).
+		ClassMethod(Sel_registerName("version"), func(self Id, cmd Sel) int32 { return 2 }).
+		Property("title", PropertyAttributes{Type: "@", Retain: true, Nonatomic: true}).
+		Protocol(proto).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if Objc_getClass("ClassBuiltWithBuilder") != cls {
+		t.Error("class should be registered")
+	}
+
+	for _, ivar := range []string{"count", "weights", "_title"} {
+		if Class_getInstanceVariable(cls, ivar) == nil {
+			t.Errorf("class should have the ivar %s", ivar)
+		}
+	}
+
+	if answer, _ := Send[int32](Class_createInstance(cls, 0), Sel_registerName("answer")); answer != 42 {
+		t.Errorf("answer should be 42: %d", answer)
+	}
+
+	if version, _ := SendClass[int32](cls, Sel_registerName("version")); version != 2 {
+		t.Errorf("version should be 2: %d", version)
+	}
+
+	if Class_getProperty(cls, "title") == nil {
+		t.Error("class should have the title property")
+	}
+
+	if !Class_conformsToProtocol(cls, proto) {
+		t.Error("class should conform to ClassBuilderProto")
+	}
+}
+
+func TestClassBuilderMethodWithTypes(t *testing.T) {
+	sel := Sel_registerName("isReady")
+
+	cls, err := NewClassBuilder("ClassBuiltWithTypedMethod", Objc_getClass("NSObject")).
+		MethodWithTypes(sel, func(self Id, cmd Sel) bool { return true }, "c@:").
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if types := Method_getTypeEncoding(Class_getInstanceMethod(cls, sel)); types != "c@:" {
+		t.Errorf("isReady types should be c@:: %s", types)
+	}
+
+	_, err = NewClassBuilder("ClassBuiltWithMismatchedMethod", Objc_getClass("NSObject")).
+		MethodWithTypes(sel, func(self Id, cmd Sel) float64 { return 0 }, "c@:").
+		Register()
+
+	if err == nil {
+		t.Error("mismatched method types should be rejected")
+	}
+}
+
+func TestClassBuilderErrors(t *testing.T) {
+	answer := Sel_registerName("answer")
+
+	cls, err := NewClassBuilder("ClassBuiltWithErrors", Objc_getClass("NSObject")).
+		Ivar("count", "q").
+		Ivar("count", "q").
+		Ivar("color", "{CGColor}").
+		Method(answer, func(self Id, cmd Sel) int32 { return 42 }).
+		Method(answer, func(self Id, cmd Sel) int32 { return 42 }).
+		Method(Sel_registerName("bad"), func(self Id) {}).
+		Protocol(nil).
+		Register()
+
+	if cls != nil {
+		t.Error("class should be nil")
+	}
+
+	var buildErr *BuildError
+
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("error should be a *BuildError: %v", err)
+	}
+
+	if l := len(buildErr.Errors); l != 5 {
+		t.Errorf("5 steps should have failed: %d: %v", l, err)
+	}
+
+	if !strings.Contains(err.Error(), "ClassBuiltWithErrors") {
+		t.Errorf("error should name the class: %v", err)
+	}
+
+	if Objc_getClass("ClassBuiltWithErrors") != nil {
+		t.Error("class should have been disposed")
+	}
+
+	if _, err = NewClassBuilder("ClassBuiltWithErrors", Objc_getClass("NSObject")).Register(); err != nil {
+		t.Errorf("class name should be reusable after a failure: %v", err)
+	}
+}
+
+func TestClassBuilderFreesImps(t *testing.T) {
+	goImpMutex.Lock()
+	count := len(goImps)
+	goImpMutex.Unlock()
+
+	_, err := NewClassBuilder("ClassBuiltWithLeakedImps", Objc_getClass("NSObject")).
+		Method(Sel_registerName("answer"), func(self Id, cmd Sel) int32 { return 42 }).
+		Property("title", PropertyAttributes{Type: "@", Retain: true}).
+		Property("title", PropertyAttributes{Type: "@", Retain: true}).
+		Register()
+
+	if err == nil {
+		t.Fatal("synthesizing title twice should have failed")
+	}
+
+	goImpMutex.Lock()
+	defer goImpMutex.Unlock()
+
+	if l := len(goImps); l != count {
+		t.Errorf("implementations should have been freed: %d left", l-count)
+	}
+}
+
+func TestClassBuilderExistingClass(t *testing.T) {
+	if _, err := NewClassBuilder("NSObject", nil).Register(); err == nil {
+		t.Error("building NSObject should have failed")
+	}
+
+	if _, err := NewClassBuilder("", nil).Register(); err == nil {
+		t.Error("building a class without name should have failed")
+	}
+}
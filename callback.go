@@ -0,0 +1,17 @@
+package objc
+
+// #ifdef __APPLE__
+// #include <ffi/ffi.h>
+// #else
+// #include <ffi.h>
+// #endif
+import "C"
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+//export goImpCallback
+func goImpCallback(cif *C.ffi_cif, ret unsafe.Pointer, args *unsafe.Pointer, userdata unsafe.Pointer) {
+	cgo.Handle(uintptr(userdata)).Value().(*goImp).call(ret, args)
+}
@@ -1,5 +1,5 @@
 package objc
 
 // #cgo CFLAGS: -W -Wall -Wno-unused-parameter -Wno-unused-function -O3
-// #cgo LDFLAGS: -lobjc
+// #cgo LDFLAGS: -lobjc -lffi
 import "C"
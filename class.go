@@ -3,7 +3,13 @@ package objc
 // #include <stdlib.h>
 // #include <objc/runtime.h>
 import "C"
-import "unsafe"
+import (
+	"math/bits"
+	"runtime"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
 
 type Class C.Class
 
@@ -50,6 +56,26 @@ func Class_addIvar(cls Class, name string, size uint, alignment uint8, types str
 	return C.class_addIvar(cls, cname, C.size_t(size), C.uint8_t(alignment), ctypes) != 0
 }
 
+// Class_addIvarWithType adds an instance variable like Class_addIvar, with
+// the size and the alignment derived from types for the running platform.
+// It returns false when types is not a sized encoding or when the platform
+// is not supported.
+func Class_addIvarWithType(cls Class, name string, types string) bool {
+	arch, err := encoding.ArchOf(runtime.GOOS, runtime.GOARCH)
+
+	if err != nil {
+		return false
+	}
+
+	size, alignment, err := encoding.SizeAlign(types, arch)
+
+	if err != nil {
+		return false
+	}
+
+	return Class_addIvar(cls, name, uint(size), uint8(bits.TrailingZeros(uint(alignment))), types)
+}
+
 func Class_copyIvarList(cls Class) (ivars []Ivar) {
 	var coutCount C.uint
 
@@ -100,6 +126,12 @@ func Class_addMethod(cls Class, name Sel, imp Imp, types string) bool {
 	return C.class_addMethod(cls, name, imp, ctype) != 0
 }
 
+// Class_addClassMethod adds a class method to cls by adding it to its
+// metaclass. cls does not need to be registered.
+func Class_addClassMethod(cls Class, name Sel, imp Imp, types string) bool {
+	return Class_addMethod(metaClass(cls), name, imp, types)
+}
+
 func Class_getInstanceMethod(aClass Class, aSelector Sel) Method {
 	return Method(C.class_getInstanceMethod(aClass, aSelector))
 }
@@ -126,6 +158,38 @@ func Class_copyMethodList(cls Class) (methods []Method) {
 	return
 }
 
+// Class_copyClassMethodList returns the class methods implemented by cls,
+// which are the methods of its metaclass.
+func Class_copyClassMethodList(cls Class) []Method {
+	return Class_copyMethodList(metaClass(cls))
+}
+
+// MethodEntry is a method labeled as a class or an instance method.
+type MethodEntry struct {
+	Method        Method
+	IsClassMethod bool
+}
+
+// Declaration returns the Objective-C declaration of the method, such as
+// "+ (id)alloc;".
+func (entry MethodEntry) Declaration() (string, error) {
+	return MethodDeclaration(entry.Method, entry.IsClassMethod)
+}
+
+// CopyMethodList returns the class methods then the instance methods
+// implemented by cls. Inherited methods are not included.
+func CopyMethodList(cls Class) (entries []MethodEntry) {
+	for _, method := range Class_copyClassMethodList(cls) {
+		entries = append(entries, MethodEntry{Method: method, IsClassMethod: true})
+	}
+
+	for _, method := range Class_copyMethodList(cls) {
+		entries = append(entries, MethodEntry{Method: method})
+	}
+
+	return
+}
+
 func Class_replaceMethod(cls Class, name Sel, imp Imp, types string) Imp {
 	ctype := C.CString(types)
 	defer C.free(unsafe.Pointer(ctype))
@@ -255,6 +319,11 @@ func Class_getImageName(cls Class) string {
 	return C.GoString(C.class_getImageName(cls))
 }
 
+// metaClass returns the metaclass of cls, which may not be registered yet.
+func metaClass(cls Class) Class {
+	return Object_getClass(Id(unsafe.Pointer(cls)))
+}
+
 func nextClass(list *C.Class) *C.Class {
 	ptr := uintptr(unsafe.Pointer(list)) + unsafe.Sizeof(*list)
 	return (*C.Class)(unsafe.Pointer(ptr))
@@ -1,11 +1,6 @@
 package objc
 
-import (
-	"testing"
-	"unsafe"
-
-	"github.com/achille-roussel/go-ffi"
-)
+import "testing"
 
 func TestClassGetName(t *testing.T) {
 	name := "NSObject"
@@ -66,9 +61,9 @@ func TestClassGetCustomInstanceSize(t *testing.T) {
 	className := "CustomClassForSize"
 	class := Objc_allocateClassPair(nil, className, 0)
 
-	Class_addIvar(class, "a", 4, 0, "i")
-	Class_addIvar(class, "b", 4, 0, "i")
-	Class_addIvar(class, "c", 4, 0, "i")
+	Class_addIvarWithType(class, "a", "i")
+	Class_addIvarWithType(class, "b", "i")
+	Class_addIvarWithType(class, "c", "i")
 
 	if size := Class_getInstanceSize(class); size != 24 {
 		t.Errorf("size should be equal to 24: %d", size)
@@ -107,6 +102,23 @@ func TestClassAddIvar(t *testing.T) {
 	}
 }
 
+func TestClassAddIvarWithType(t *testing.T) {
+	className := "AddIvarWithTypeClass"
+	class := Objc_allocateClassPair(nil, className, 0)
+
+	if !Class_addIvarWithType(class, "origin", "{CGPoint=dd}") {
+		t.Errorf("add origin to %s failed", className)
+	}
+
+	if Class_addIvarWithType(class, "color", "^{CGColor") {
+		t.Errorf("add color to %s should have failed", className)
+	}
+
+	if types := Ivar_getTypeEncoding(Class_getInstanceVariable(class, "origin")); types != "{CGPoint=dd}" {
+		t.Errorf("origin type encoding should be {CGPoint=dd}: %s", types)
+	}
+}
+
 func TestClassAddSameIvar(t *testing.T) {
 	className := "AddIvarClass"
 	class := Objc_allocateClassPair(nil, className, 0)
@@ -208,10 +220,10 @@ func TestClassAddMethod(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
 
-	if !Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), "v@:") {
+	if !Class_addMethod(class, sel, imp, "v@:") {
 		t.Errorf("failed to add %s to %s", methodeName, className)
 	}
 }
@@ -221,13 +233,13 @@ func TestClassAddExistingMethod(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA"
-	closureA := ffi.Closure(func(id Id, sel Sel) {})
-	closureB := ffi.Closure(func(id Id, sel Sel, n int) {})
+	impA := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
+	impB := newTestImp(t, func(id Id, sel Sel, n int32) {}, "v@:i")
 	sel := Sel_registerName(methodeName)
 
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closureA.Pointer())), "v@:")
+	Class_addMethod(class, sel, impA, "v@:")
 
-	if Class_addMethod(class, sel, Imp(unsafe.Pointer(closureB.Pointer())), "v@:i") {
+	if Class_addMethod(class, sel, impB, "v@:i") {
 		t.Errorf("add %s to %s should have failde", methodeName, className)
 	}
 }
@@ -237,10 +249,10 @@ func TestClassGetInstanceMethod(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
 
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), "v@:")
+	Class_addMethod(class, sel, imp, "v@:")
 
 	if method := Class_getInstanceMethod(class, sel); method == nil {
 		t.Error("method should not be nil")
@@ -264,10 +276,10 @@ func TestClassGetClassMethod(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
 
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), "v@:")
+	Class_addMethod(class, sel, imp, "v@:")
 
 	if method := Class_getClassMethod(class, sel); method == nil {
 		t.Error("method should not be nil")
@@ -290,13 +302,13 @@ func TestClassCopyMethodList(t *testing.T) {
 	className := "ClassWithMethods"
 	class := Objc_allocateClassPair(nil, className, 0)
 
-	closureA := ffi.Closure(func(id Id, sel Sel) {})
-	closureB := ffi.Closure(func(id Id, sel Sel, n int) {})
+	impA := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
+	impB := newTestImp(t, func(id Id, sel Sel, n int32) {}, "v@:i")
 	selA := Sel_registerName("MethodA")
 	selB := Sel_registerName("MethodB")
 
-	Class_addMethod(class, selA, Imp(unsafe.Pointer(closureA.Pointer())), "v@:")
-	Class_addMethod(class, selB, Imp(unsafe.Pointer(closureB.Pointer())), "v@:i")
+	Class_addMethod(class, selA, impA, "v@:")
+	Class_addMethod(class, selB, impB, "v@:i")
 
 	methods := Class_copyMethodList(class)
 
@@ -320,10 +332,8 @@ func TestClassReplaceMethod(t *testing.T) {
 	className := "ClassWithMethodToBeReplaced"
 	class := Objc_allocateClassPair(nil, className, 0)
 
-	closureA := ffi.Closure(func(id Id, sel Sel) {})
-	closureB := ffi.Closure(func(id Id, sel Sel) {})
-	impA := Imp(unsafe.Pointer(closureA.Pointer()))
-	impB := Imp(unsafe.Pointer(closureB.Pointer()))
+	impA := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
+	impB := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName("MethodToBeReplaced")
 
 	Class_addMethod(class, sel, impA, "v@:")
@@ -338,9 +348,8 @@ func TestClassReplaceNonexistentMethod(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
-	imp := Imp(unsafe.Pointer(closure.Pointer()))
 
 	if retImp := Class_replaceMethod(class, sel, imp, "v@:"); retImp != nil {
 		t.Errorf("retImp should be nil: %#v", retImp)
@@ -353,9 +362,8 @@ func TestClassGetMethodImplementation(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodeName := "MethodA:"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
-	imp := Imp(unsafe.Pointer(closure.Pointer()))
 	Class_addMethod(class, sel, imp, "v@:")
 
 	if retImp := Class_getMethodImplementation(class, sel); retImp != imp {
@@ -369,9 +377,8 @@ func TestClassGetMethodImplementationStret(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodeName := "MethodA:"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
-	imp := Imp(unsafe.Pointer(closure.Pointer()))
 	Class_addMethod(class, sel, imp, "v@:")
 
 	if retImp := Class_getMethodImplementation_stret(class, sel); retImp != imp {
@@ -384,9 +391,8 @@ func TestClassRespondsToSelector(t *testing.T) {
 	class := Objc_allocateClassPair(nil, className, 0)
 
 	methodeName := "MethodA:"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodeName)
-	imp := Imp(unsafe.Pointer(closure.Pointer()))
 	Class_addMethod(class, sel, imp, "v@:")
 
 	if !Class_respondsToSelector(class, sel) {
@@ -574,3 +580,52 @@ func TestClassGetImageName(t *testing.T) {
 		t.Errorf("image should be %s: %s", libName, image)
 	}
 }
+
+func TestClassAddClassMethod(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithClassMethod", 0)
+	sel := Sel_registerName("sharedValue")
+	imp := newTestImp(t, func(self Id, cmd Sel) int32 { return 7 }, "i@:")
+
+	if !Class_addClassMethod(class, sel, imp, "i@:") {
+		t.Fatal("adding sharedValue failed")
+	}
+
+	Objc_registerClassPair(class)
+
+	if Class_getClassMethod(class, sel) == nil {
+		t.Error("sharedValue should be a class method")
+	}
+
+	if Class_getInstanceMethod(class, sel) != nil {
+		t.Error("sharedValue should not be an instance method")
+	}
+
+	if value, _ := SendClass[int32](class, sel); value != 7 {
+		t.Errorf("sharedValue should be 7: %d", value)
+	}
+}
+
+func TestCopyMethodList(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithLabeledMethods", 0)
+	Class_addClassMethod(class, Sel_registerName("create"), newTestImp(t, func(self Id, cmd Sel) {}, "v@:"), "v@:")
+	Class_addMethod(class, Sel_registerName("run"), newTestImp(t, func(self Id, cmd Sel) {}, "v@:"), "v@:")
+	Objc_registerClassPair(class)
+
+	if methods := Class_copyClassMethodList(class); len(methods) != 1 {
+		t.Errorf("class should have 1 class method: %d", len(methods))
+	}
+
+	entries := CopyMethodList(class)
+
+	if l := len(entries); l != 2 {
+		t.Fatalf("class should have 2 methods: %d", l)
+	}
+
+	expected := []string{"+ (void)create;", "- (void)run;"}
+
+	for i, entry := range entries {
+		if decl, _ := entry.Declaration(); decl != expected[i] {
+			t.Errorf("method %d should be declared as %s: %s", i, expected[i], decl)
+		}
+	}
+}
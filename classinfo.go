@@ -0,0 +1,300 @@
+package objc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// ClassInfo is a snapshot of the definition of a class, as returned by
+// Describe. It can be marshalled to JSON.
+type ClassInfo struct {
+	Name         string         `json:"name"`
+	Superclasses []string       `json:"superclasses,omitempty"`
+	IsMetaClass  bool           `json:"isMetaClass,omitempty"`
+	Version      int            `json:"version"`
+	ImageName    string         `json:"imageName,omitempty"`
+	InstanceSize uint           `json:"instanceSize"`
+	Ivars        []IvarInfo     `json:"ivars,omitempty"`
+	Methods      []MethodInfo   `json:"methods,omitempty"`
+	ClassMethods []MethodInfo   `json:"classMethods,omitempty"`
+	Properties   []PropertyInfo `json:"properties,omitempty"`
+	Protocols    []string       `json:"protocols,omitempty"`
+}
+
+// IvarInfo describes an instance variable.
+type IvarInfo struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+}
+
+// MethodInfo describes a method. Signature is the type encoding without
+// frame offsets and Declaration its Objective-C declaration. They are
+// empty when the type encoding cannot be parsed.
+type MethodInfo struct {
+	Name        string `json:"name"`
+	Types       string `json:"types"`
+	Signature   string `json:"signature,omitempty"`
+	Declaration string `json:"declaration,omitempty"`
+}
+
+// PropertyInfo describes a property. Parsed holds the typed attributes
+// when Attributes can be parsed.
+type PropertyInfo struct {
+	Name       string              `json:"name"`
+	Attributes string              `json:"attributes"`
+	Parsed     *PropertyAttributes `json:"parsed,omitempty"`
+}
+
+// Describe returns a snapshot of the definition of cls: its superclass
+// chain, the instance variables, methods, properties and protocols it
+// declares, and its class methods, which are those of its metaclass. Inherited members are not included. It
+// returns nil when cls is nil.
+func Describe(cls Class) *ClassInfo {
+	if cls == nil {
+		return nil
+	}
+
+	info := &ClassInfo{
+		Name:         Class_getName(cls),
+		IsMetaClass:  Class_isMetaClass(cls),
+		Version:      Class_getVersion(cls),
+		ImageName:    Class_getImageName(cls),
+		InstanceSize: Class_getInstanceSize(cls),
+	}
+
+	for superclass := Class_getSuperclass(cls); superclass != nil; superclass = Class_getSuperclass(superclass) {
+		info.Superclasses = append(info.Superclasses, Class_getName(superclass))
+	}
+
+	for _, ivar := range Class_copyIvarList(cls) {
+		info.Ivars = append(info.Ivars, IvarInfo{
+			Name:   Ivar_getName(ivar),
+			Type:   Ivar_getTypeEncoding(ivar),
+			Offset: Ivar_getOffset(ivar),
+		})
+	}
+
+	for _, entry := range CopyMethodList(cls) {
+		method := describeMethod(entry)
+
+		if entry.IsClassMethod {
+			info.ClassMethods = append(info.ClassMethods, method)
+		} else {
+			info.Methods = append(info.Methods, method)
+		}
+	}
+
+	for _, property := range Class_copyPropertyList(cls) {
+		info.Properties = append(info.Properties, describeProperty(property))
+	}
+
+	for _, proto := range Class_copyProtocolList(cls) {
+		info.Protocols = append(info.Protocols, Protocol_getName(proto))
+	}
+
+	return info
+}
+
+func describeMethod(entry MethodEntry) MethodInfo {
+	info := MethodInfo{
+		Name:  Sel_getName(Method_getName(entry.Method)),
+		Types: Method_getTypeEncoding(entry.Method),
+	}
+
+	if sig, err := encoding.ParseMethodSignature(info.Types); err == nil {
+		info.Signature = sig.String()
+		info.Declaration = sig.Decl(info.Name, entry.IsClassMethod)
+	}
+
+	return info
+}
+
+func describeProperty(property Property) PropertyInfo {
+	info := PropertyInfo{
+		Name:       Property_getName(property),
+		Attributes: Property_getAttributes(property),
+	}
+
+	if attrs, err := ParsePropertyAttributes(info.Attributes); err == nil {
+		info.Parsed = &attrs
+	}
+
+	return info
+}
+
+// String returns the Objective-C @interface declaration of the class,
+// with the offset of each instance variable as a comment.
+func (info *ClassInfo) String() string {
+	var b strings.Builder
+
+	b.WriteString("@interface " + info.Name)
+
+	if len(info.Superclasses) != 0 {
+		b.WriteString(" : " + info.Superclasses[0])
+	}
+
+	if len(info.Protocols) != 0 {
+		b.WriteString(" <" + strings.Join(info.Protocols, ", ") + ">")
+	}
+
+	if len(info.Ivars) != 0 {
+		b.WriteString(" {\n")
+
+		for _, ivar := range info.Ivars {
+			fmt.Fprintf(&b, "    %s; // offset %d\n", ivarDecl(ivar), ivar.Offset)
+		}
+
+		b.WriteString("}")
+	}
+
+	b.WriteString("\n")
+
+	writeProperties(&b, info.Properties)
+	writeMethods(&b, info.ClassMethods, info.Methods)
+
+	b.WriteString("@end\n")
+	return b.String()
+}
+
+func writeProperties(b *strings.Builder, properties []PropertyInfo) {
+	if len(properties) == 0 {
+		return
+	}
+
+	b.WriteString("\n")
+
+	for _, property := range properties {
+		b.WriteString(propertyDecl(property) + "\n")
+	}
+}
+
+func writeMethods(b *strings.Builder, classMethods []MethodInfo, methods []MethodInfo) {
+	if len(classMethods)+len(methods) == 0 {
+		return
+	}
+
+	b.WriteString("\n")
+
+	for _, method := range classMethods {
+		b.WriteString(methodDecl(method, "+") + "\n")
+	}
+
+	for _, method := range methods {
+		b.WriteString(methodDecl(method, "-") + "\n")
+	}
+}
+
+func ivarDecl(ivar IvarInfo) string {
+	t, err := encoding.Parse(ivar.Type)
+
+	if err != nil {
+		return fmt.Sprintf("/* %s */ %s", ivar.Type, ivar.Name)
+	}
+
+	return t.Decl(ivar.Name)
+}
+
+func propertyDecl(property PropertyInfo) string {
+	if property.Parsed != nil {
+		if decl, err := property.Parsed.Declaration(property.Name); err == nil {
+			return decl
+		}
+	}
+
+	return fmt.Sprintf("@property %s; // %s", property.Name, property.Attributes)
+}
+
+func methodDecl(method MethodInfo, prefix string) string {
+	if method.Declaration != "" {
+		return method.Declaration
+	}
+
+	return fmt.Sprintf("%s %s; // %s", prefix, method.Name, method.Types)
+}
+
+// ProtocolInfo is a snapshot of the definition of a protocol, as returned
+// by DescribeProtocol. It can be marshalled to JSON.
+type ProtocolInfo struct {
+	Name                 string         `json:"name"`
+	Protocols            []string       `json:"protocols,omitempty"`
+	Methods              []MethodInfo   `json:"methods,omitempty"`
+	ClassMethods         []MethodInfo   `json:"classMethods,omitempty"`
+	OptionalMethods      []MethodInfo   `json:"optionalMethods,omitempty"`
+	OptionalClassMethods []MethodInfo   `json:"optionalClassMethods,omitempty"`
+	Properties           []PropertyInfo `json:"properties,omitempty"`
+}
+
+// DescribeProtocol returns a snapshot of the definition of proto: the
+// protocols it adopts, its required and optional methods, and its
+// properties. It returns nil when proto is nil.
+func DescribeProtocol(proto Protocol) *ProtocolInfo {
+	if proto == nil {
+		return nil
+	}
+
+	info := &ProtocolInfo{Name: Protocol_getName(proto)}
+
+	for _, parent := range Protocol_copyProtocolList(proto) {
+		info.Protocols = append(info.Protocols, Protocol_getName(parent))
+	}
+
+	lists := []struct {
+		methods          *[]MethodInfo
+		isRequiredMethod bool
+		isInstanceMethod bool
+	}{
+		{&info.Methods, true, true},
+		{&info.ClassMethods, true, false},
+		{&info.OptionalMethods, false, true},
+		{&info.OptionalClassMethods, false, false},
+	}
+
+	for _, list := range lists {
+		for _, description := range Protocol_copyMethodDescriptionList(proto, list.isRequiredMethod, list.isInstanceMethod) {
+			method := MethodInfo{
+				Name:  Sel_getName(description.Name),
+				Types: description.Types,
+			}
+
+			if sig, err := description.Signature(); err == nil {
+				method.Signature = sig.String()
+				method.Declaration = sig.Decl(method.Name, !list.isInstanceMethod)
+			}
+
+			*list.methods = append(*list.methods, method)
+		}
+	}
+
+	for _, property := range Protocol_copyPropertyList(proto) {
+		info.Properties = append(info.Properties, describeProperty(property))
+	}
+
+	return info
+}
+
+// String returns the Objective-C @protocol declaration of the protocol.
+func (info *ProtocolInfo) String() string {
+	var b strings.Builder
+
+	b.WriteString("@protocol " + info.Name)
+
+	if len(info.Protocols) != 0 {
+		b.WriteString(" <" + strings.Join(info.Protocols, ", ") + ">")
+	}
+
+	b.WriteString("\n")
+
+	writeProperties(&b, info.Properties)
+	writeMethods(&b, info.ClassMethods, info.Methods)
+
+	if len(info.OptionalClassMethods)+len(info.OptionalMethods) != 0 {
+		b.WriteString("\n@optional")
+		writeMethods(&b, info.OptionalClassMethods, info.OptionalMethods)
+	}
+
+	b.WriteString("@end\n")
+	return b.String()
+}
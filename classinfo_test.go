@@ -0,0 +1,130 @@
+package objc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	proto := Objc_allocateProtocol("DescribedProto")
+	Objc_registerProtocol(proto)
+
+	cls, err := NewClassBuilder("DescribedClass", Objc_getClass("NSObject")).
+		Ivar("count", "q").
+		Method(Sel_registerName("setFoo:"), func(self Id, cmd Sel, foo int32) {}).
+		ClassMethod(Sel_registerName("version"), func(self Id, cmd Sel) int32 { return 1 }).
+		Property("title", PropertyAttributes{Type: "@", Copy: true, Nonatomic: true}).
+		Protocol(proto).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := Describe(cls)
+
+	if info.Name != "DescribedClass" {
+		t.Errorf("name should be DescribedClass: %s", info.Name)
+	}
+
+	if !reflect.DeepEqual(info.Superclasses, []string{"NSObject"}) {
+		t.Errorf("superclasses should be [NSObject]: %v", info.Superclasses)
+	}
+
+	if info.InstanceSize != Class_getInstanceSize(cls) {
+		t.Errorf("instance size should be %d: %d", Class_getInstanceSize(cls), info.InstanceSize)
+	}
+
+	if len(info.Ivars) != 2 || info.Ivars[0].Name != "count" || info.Ivars[0].Type != "q" {
+		t.Errorf("ivars should be count and _title: %+v", info.Ivars)
+	}
+
+	if len(info.ClassMethods) != 1 || info.ClassMethods[0].Declaration != "+ (int)version;" {
+		t.Errorf("class methods should be version: %+v", info.ClassMethods)
+	}
+
+	if len(info.Properties) != 1 || info.Properties[0].Parsed == nil || !info.Properties[0].Parsed.Copy {
+		t.Errorf("properties should be a copied title: %+v", info.Properties)
+	}
+
+	if !reflect.DeepEqual(info.Protocols, []string{"DescribedProto"}) {
+		t.Errorf("protocols should be [DescribedProto]: %v", info.Protocols)
+	}
+
+	for _, expected := range []string{
+		"@interface DescribedClass : NSObject <DescribedProto> {",
+		"long long count; // offset",
+		"@property (nonatomic, copy) id title;",
+		"+ (int)version;",
+		"- (void)setFoo:(int)foo;",
+		"@end",
+	} {
+		if s := info.String(); !strings.Contains(s, expected) {
+			t.Errorf("description should contain %q:\n%s", expected, s)
+		}
+	}
+
+	data, err := json.Marshal(info)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded ClassInfo
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(&decoded, info) {
+		t.Errorf("decoded info should be %+v: %+v", info, decoded)
+	}
+}
+
+func TestDescribeNil(t *testing.T) {
+	if info := Describe(nil); info != nil {
+		t.Errorf("nil class should not be described: %+v", info)
+	}
+}
+
+func TestDescribeProtocol(t *testing.T) {
+	parent, err := NewProtocolBuilder("DescribedParentProto").Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proto, err := NewProtocolBuilder("DescribedChildProto").
+		Method(Sel_registerName("count"), "q@:").
+		OptionalClassMethod(Sel_registerName("version"), "i@:").
+		Property("title", PropertyAttributes{Type: "@", Copy: true}).
+		Adopt(parent).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := DescribeProtocol(proto)
+
+	if !reflect.DeepEqual(info.Protocols, []string{"DescribedParentProto"}) {
+		t.Errorf("protocols should be [DescribedParentProto]: %v", info.Protocols)
+	}
+
+	expected := `@protocol DescribedChildProto <DescribedParentProto>
+
+@property (copy) id title;
+
+- (long long)count;
+
+@optional
++ (int)version;
+@end
+`
+
+	if s := info.String(); s != expected {
+		t.Errorf("description should be:\n%s\n%s", expected, s)
+	}
+}
@@ -0,0 +1,75 @@
+// Command objc-dump prints the Objective-C declarations of the classes
+// registered in the runtime, in the manner of class-dump.
+//
+// Usage:
+//
+//	objc-dump [flags]
+//
+// The flags are:
+//
+//	-images          list the loaded images and exit
+//	-image path      dump the classes of the image path, or of the image
+//	                 whose base name is path
+//	-pattern glob    dump the classes whose name matches glob, as with
+//	                 path.Match; every class when neither -image nor
+//	                 -pattern is set
+//	-protocols       also dump the protocols adopted by the dumped classes
+//	-json            print the declarations as JSON
+//	-load path       an Objective-C library or framework to load first,
+//	                 may be repeated
+//
+// Classes built at runtime by a Go program belong to no image and cannot
+// be loaded into objc-dump, since a process cannot host two Go runtimes.
+// The program, or one of its tests, prints them with objc.Dump instead,
+// which takes the same options:
+//
+//	objc.Dump(os.Stdout, objc.DumpOptions{Pattern: "Model*"})
+//
+// It works with the Apple runtime and with the GNUstep libobjc2 runtime.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	objc "github.com/maxence-charriere/go-objcruntime"
+	"github.com/maxence-charriere/go-objcruntime/internal/dl"
+)
+
+func main() {
+	var libs dl.Libraries
+	var opts objc.DumpOptions
+
+	images := flag.Bool("images", false, "list the loaded images and exit")
+	flag.StringVar(&opts.Image, "image", "", "dump the classes of an image")
+	flag.StringVar(&opts.Pattern, "pattern", "", "dump the classes whose name matches a glob pattern")
+	flag.BoolVar(&opts.Protocols, "protocols", false, "also dump the protocols adopted by the dumped classes")
+	flag.BoolVar(&opts.JSON, "json", false, "print the declarations as JSON")
+	flag.Var(&libs, "load", "an Objective-C library to load first, may be repeated")
+	flag.Parse()
+
+	for _, lib := range libs {
+		if err := dl.Open(lib); err != nil {
+			fmt.Fprintln(os.Stderr, "objc-dump:", err)
+			os.Exit(1)
+		}
+	}
+
+	if *images {
+		names, _ := objc.Objc_copyImageNames()
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Println(name)
+		}
+
+		return
+	}
+
+	if err := objc.Dump(os.Stdout, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "objc-dump:", err)
+		os.Exit(1)
+	}
+}
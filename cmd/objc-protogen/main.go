@@ -0,0 +1,87 @@
+// Command objc-protogen generates a Go interface mirroring an Objective-C
+// protocol registered in the runtime, along with a function registering
+// implementations of the interface as conforming classes.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate objc-protogen -protocol NSTableViewDelegate -load /System/Library/Frameworks/AppKit.framework/AppKit
+//
+// Usage:
+//
+//	objc-protogen -protocol name [flags]
+//
+// The flags are:
+//
+//	-protocol name   the protocol to mirror
+//	-interface name  the name of the Go interface, the protocol name by default
+//	-package name    the name of the generated package, $GOPACKAGE by default
+//	-output file     the generated file, name_protocol.go by default
+//	-exclude list    comma-separated protocols to skip, NSObject by default
+//	-load path       a library to load before looking up the protocol, may be repeated
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	objc "github.com/maxence-charriere/go-objcruntime"
+	"github.com/maxence-charriere/go-objcruntime/internal/dl"
+)
+
+func main() {
+	var libs dl.Libraries
+
+	protocol := flag.String("protocol", "", "the protocol to mirror")
+	iface := flag.String("interface", "", "the name of the Go interface, the protocol name by default")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "the name of the generated package")
+	output := flag.String("output", "", "the generated file, name_protocol.go by default")
+	exclude := flag.String("exclude", "NSObject", "comma-separated protocols to skip")
+	flag.Var(&libs, "load", "a library to load before looking up the protocol, may be repeated")
+	flag.Parse()
+
+	if err := run(*protocol, *iface, *pkg, *output, *exclude, libs); err != nil {
+		fmt.Fprintln(os.Stderr, "objc-protogen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(protocol, iface, pkg, output, exclude string, libs []string) error {
+	if protocol == "" {
+		return fmt.Errorf("missing -protocol")
+	}
+
+	for _, lib := range libs {
+		if err := dl.Open(lib); err != nil {
+			return err
+		}
+	}
+
+	proto := objc.Objc_getProtocol(protocol)
+
+	if proto == nil {
+		return fmt.Errorf("protocol %s is not registered", protocol)
+	}
+
+	options := objc.ProtocolInterfaceOptions{
+		Package:   pkg,
+		Interface: iface,
+	}
+
+	if exclude != "" {
+		options.Exclude = strings.Split(exclude, ",")
+	}
+
+	src, err := objc.GenerateProtocolInterface(proto, options)
+
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = strings.ToLower(protocol) + "_protocol.go"
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
@@ -0,0 +1,121 @@
+package objc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConformanceIssue is a required method of a protocol that a class does
+// not implement, or implements with an incompatible signature.
+type ConformanceIssue struct {
+	Protocol      string
+	Selector      string
+	IsClassMethod bool
+	Types         string
+
+	// Err describes the signature mismatch. It is nil when the method is
+	// missing.
+	Err error
+}
+
+func (issue ConformanceIssue) String() string {
+	prefix := "-"
+
+	if issue.IsClassMethod {
+		prefix = "+"
+	}
+
+	if issue.Err == nil {
+		return fmt.Sprintf("%s[%s %s] is missing", prefix, issue.Protocol, issue.Selector)
+	}
+
+	return fmt.Sprintf("%s[%s %s] does not match %s: %v", prefix, issue.Protocol, issue.Selector, issue.Types, issue.Err)
+}
+
+// ConformanceError lists the issues found by VerifyConformance.
+type ConformanceError struct {
+	Class    string
+	Protocol string
+	Issues   []ConformanceIssue
+}
+
+func (err *ConformanceError) Error() string {
+	issues := make([]string, len(err.Issues))
+
+	for i, issue := range err.Issues {
+		issues[i] = issue.String()
+	}
+
+	return fmt.Sprintf("objc: %s does not conform to %s: %s", err.Class, err.Protocol, strings.Join(issues, "; "))
+}
+
+// VerifyConformance checks that cls implements, or inherits, every
+// required instance and class method of proto and of the protocols proto
+// adopts, with compatible signatures. It returns a *ConformanceError
+// listing each missing or mismatched method. cls does not need to be
+// registered.
+func VerifyConformance(cls Class, proto Protocol) error {
+	var issues []ConformanceIssue
+
+	visited := map[Protocol]bool{}
+	protocols := []Protocol{proto}
+
+	for len(protocols) != 0 {
+		p := protocols[0]
+		protocols = protocols[1:]
+
+		if visited[p] {
+			continue
+		}
+
+		visited[p] = true
+		protocols = append(protocols, Protocol_copyProtocolList(p)...)
+
+		for _, isInstanceMethod := range []bool{true, false} {
+			for _, description := range Protocol_copyMethodDescriptionList(p, true, isInstanceMethod) {
+				if issue, ok := checkRequiredMethod(cls, p, description, isInstanceMethod); !ok {
+					issues = append(issues, issue)
+				}
+			}
+		}
+	}
+
+	if len(issues) != 0 {
+		return &ConformanceError{
+			Class:    Class_getName(cls),
+			Protocol: Protocol_getName(proto),
+			Issues:   issues,
+		}
+	}
+
+	return nil
+}
+
+func checkRequiredMethod(cls Class, proto Protocol, description MethodDescription, isInstanceMethod bool) (ConformanceIssue, bool) {
+	issue := ConformanceIssue{
+		Protocol:      Protocol_getName(proto),
+		Selector:      Sel_getName(description.Name),
+		IsClassMethod: !isInstanceMethod,
+		Types:         description.Types,
+	}
+
+	var method Method
+
+	if isInstanceMethod {
+		method = Class_getInstanceMethod(cls, description.Name)
+	} else {
+		method = Class_getClassMethod(cls, description.Name)
+	}
+
+	if method == nil {
+		return issue, false
+	}
+
+	if description.Types != "" {
+		if issue.Err = CheckMethodTypes(method, description.Types); issue.Err != nil {
+			return issue, false
+		}
+	}
+
+	return issue, true
+}
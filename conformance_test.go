@@ -0,0 +1,118 @@
+package objc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyConformance(t *testing.T) {
+	parent := Objc_allocateProtocol("ConformingParentProto")
+	Protocol_addMethodDescription(parent, Sel_registerName("reset"), "v@:", true, true)
+	Objc_registerProtocol(parent)
+
+	proto := Objc_allocateProtocol("ConformingProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("count"), "i@:", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("sharedInstance"), "@@:", true, false)
+	Protocol_addMethodDescription(proto, Sel_registerName("optionalMethod"), "v@:", false, true)
+	Protocol_addProtocol(proto, parent)
+	Objc_registerProtocol(proto)
+
+	cls, err := NewClassBuilder("ConformingClass", Objc_getClass("NSObject")).
+		Method(Sel_registerName("count"), func(self Id, cmd Sel) int32 { return 0 }).
+		Method(Sel_registerName("reset"), func(self Id, cmd Sel) {}).
+		ClassMethod(Sel_registerName("sharedInstance"), func(self Id, cmd Sel) Id { return nil }).
+		Protocol(proto).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = VerifyConformance(cls, proto); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyConformanceIssues(t *testing.T) {
+	parent := Objc_allocateProtocol("NonConformingParentProto")
+	Protocol_addMethodDescription(parent, Sel_registerName("reset"), "v@:", true, true)
+	Objc_registerProtocol(parent)
+
+	proto := Objc_allocateProtocol("NonConformingProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("count"), "i@:", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("sharedInstance"), "@@:", true, false)
+	Protocol_addMethodDescription(proto, Sel_registerName("optionalMethod"), "v@:", false, true)
+	Protocol_addProtocol(proto, parent)
+	Objc_registerProtocol(proto)
+
+	cls := Objc_allocateClassPair(Objc_getClass("NSObject"), "NonConformingClass", 0)
+	Class_addGoMethod(cls, Sel_registerName("count"), func(self Id, cmd Sel) float64 { return 0 })
+
+	err := VerifyConformance(cls, proto)
+
+	var conformanceErr *ConformanceError
+
+	if !errors.As(err, &conformanceErr) {
+		t.Fatalf("error should be a *ConformanceError: %v", err)
+	}
+
+	expected := map[string]bool{
+		"count":          false,
+		"sharedInstance": false,
+		"reset":          false,
+	}
+
+	for _, issue := range conformanceErr.Issues {
+		if _, ok := expected[issue.Selector]; !ok {
+			t.Errorf("%s should not be an issue", issue.Selector)
+		}
+
+		expected[issue.Selector] = true
+
+		if issue.Selector == "count" && issue.Err == nil {
+			t.Error("count should be reported as mismatched")
+		}
+
+		if issue.Selector != "count" && issue.Err != nil {
+			t.Errorf("%s should be reported as missing: %v", issue.Selector, issue.Err)
+		}
+
+		if isClassMethod := issue.Selector == "sharedInstance"; issue.IsClassMethod != isClassMethod {
+			t.Errorf("%s class method flag should be %v", issue.Selector, isClassMethod)
+		}
+	}
+
+	for selector, found := range expected {
+		if !found {
+			t.Errorf("%s should be reported", selector)
+		}
+	}
+}
+
+func TestClassBuilderVerifyConformance(t *testing.T) {
+	proto := Objc_allocateProtocol("BuilderVerifiedProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("count"), "i@:", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("reset"), "v@:", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("sharedInstance"), "@@:", true, false)
+	Objc_registerProtocol(proto)
+
+	_, err := NewClassBuilder("BuilderVerifiedClass", Objc_getClass("NSObject")).
+		Method(Sel_registerName("count"), func(self Id, cmd Sel) int32 { return 0 }).
+		Protocol(proto).
+		VerifyConformance().
+		Register()
+
+	var conformanceErr *ConformanceError
+
+	if !errors.As(err, &conformanceErr) {
+		t.Fatalf("error should wrap a *ConformanceError: %v", err)
+	}
+
+	if l := len(conformanceErr.Issues); l != 2 {
+		t.Errorf("2 methods should be missing: %d", l)
+	}
+
+	if Objc_getClass("BuilderVerifiedClass") != nil {
+		t.Error("BuilderVerifiedClass should not be registered")
+	}
+}
@@ -0,0 +1,167 @@
+package objc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// DumpOptions selects the classes written by Dump. Every registered class
+// is selected when neither Image nor Pattern is set.
+type DumpOptions struct {
+	// Image selects the classes of the loaded image with this path or base
+	// name.
+	Image string
+
+	// Pattern selects the classes whose name matches this glob pattern, as
+	// with path.Match.
+	Pattern string
+
+	// Protocols also writes the protocols adopted by the selected classes.
+	Protocols bool
+
+	// JSON writes the snapshots returned by Describe and DescribeProtocol
+	// instead of Objective-C declarations.
+	JSON bool
+}
+
+// Dump writes the Objective-C declarations of the classes selected by
+// opts, in the manner of class-dump, sorted by name.
+//
+// Classes built at runtime belong to no image. A Go program, such as a
+// test, can inspect the classes it builds by calling Dump once they are
+// registered:
+//
+//	objc.Dump(os.Stdout, objc.DumpOptions{Pattern: "Model*"})
+func Dump(w io.Writer, opts DumpOptions) error {
+	names, err := dumpedClassNames(opts)
+
+	if err != nil {
+		return err
+	}
+
+	var classes []*ClassInfo
+	var protocols []*ProtocolInfo
+	adopted := map[string]bool{}
+
+	for _, name := range names {
+		cls := Objc_getClass(name)
+
+		if cls == nil {
+			continue
+		}
+
+		classes = append(classes, Describe(cls))
+
+		if !opts.Protocols {
+			continue
+		}
+
+		// Adopted protocols are described directly since they may not be
+		// registered.
+		for _, proto := range Class_copyProtocolList(cls) {
+			if name := Protocol_getName(proto); !adopted[name] {
+				adopted[name] = true
+				protocols = append(protocols, DescribeProtocol(proto))
+			}
+		}
+	}
+
+	sort.Slice(protocols, func(i, j int) bool {
+		return protocols[i].Name < protocols[j].Name
+	})
+
+	if opts.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(struct {
+			Protocols []*ProtocolInfo `json:"protocols,omitempty"`
+			Classes   []*ClassInfo    `json:"classes"`
+		}{protocols, classes})
+	}
+
+	if opts.Image != "" {
+		if _, err = fmt.Fprintf(w, "// Image: %s\n\n", opts.Image); err != nil {
+			return err
+		}
+	}
+
+	for _, proto := range protocols {
+		if _, err = fmt.Fprintln(w, proto); err != nil {
+			return err
+		}
+	}
+
+	for _, cls := range classes {
+		if _, err = fmt.Fprintln(w, cls); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dumpedClassNames returns the sorted names of the classes selected by
+// opts.
+func dumpedClassNames(opts DumpOptions) ([]string, error) {
+	var names []string
+
+	if opts.Pattern != "" {
+		if _, err := path.Match(opts.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("objc: bad pattern %q: %v", opts.Pattern, err)
+		}
+	}
+
+	if opts.Image != "" {
+		image, err := findImage(opts.Image)
+
+		if err != nil {
+			return nil, err
+		}
+
+		names, _ = Objc_copyClassNamesForImage(image)
+	} else {
+		for _, cls := range Objc_copyClassList() {
+			names = append(names, Class_getName(cls))
+		}
+	}
+
+	if opts.Pattern != "" {
+		matching := names[:0]
+
+		for _, name := range names {
+			if ok, _ := path.Match(opts.Pattern, name); ok {
+				matching = append(matching, name)
+			}
+		}
+
+		names = matching
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// findImage returns the loaded image named name, or whose base name is
+// name.
+func findImage(name string) (string, error) {
+	images, _ := Objc_copyImageNames()
+
+	for _, image := range images {
+		if image == name {
+			return image, nil
+		}
+	}
+
+	for _, image := range images {
+		if filepath.Base(image) == name {
+			return image, nil
+		}
+	}
+
+	return "", fmt.Errorf("objc: image %s is not loaded", name)
+}
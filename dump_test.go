@@ -0,0 +1,76 @@
+package objc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	proto, err := NewProtocolBuilder("DumpedProto").
+		Method(Sel_registerName("count"), "q@:").
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Classes may adopt protocols that are never registered.
+	unregistered := Objc_allocateProtocol("DumpedUnregisteredProto")
+
+	_, err = NewClassBuilder("DumpedClass", Objc_getClass("NSObject")).
+		Method(Sel_registerName("count"), func(self Id, cmd Sel) int64 { return 0 }).
+		Protocol(proto).
+		Protocol(unregistered).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+
+	if err = Dump(&out, DumpOptions{Pattern: "Dumped*", Protocols: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"@protocol DumpedProto\n",
+		"@protocol DumpedUnregisteredProto\n",
+		"@interface DumpedClass : NSObject <",
+		"- (long long)count;\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output should contain %q:\n%s", expected, out.String())
+		}
+	}
+
+	if strings.Contains(out.String(), "@interface NSObject") {
+		t.Errorf("output should only contain classes matching Dumped*:\n%s", out.String())
+	}
+
+	out.Reset()
+
+	if err = Dump(&out, DumpOptions{Pattern: "DumpedClass", JSON: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var dump struct {
+		Classes []ClassInfo `json:"classes"`
+	}
+
+	if err = json.Unmarshal(out.Bytes(), &dump); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dump.Classes) != 1 || dump.Classes[0].Name != "DumpedClass" {
+		t.Errorf("JSON output should describe DumpedClass: %s", out.String())
+	}
+}
+
+func TestDumpBadPattern(t *testing.T) {
+	if err := Dump(&bytes.Buffer{}, DumpOptions{Pattern: "["}); err == nil {
+		t.Error("bad pattern should be rejected")
+	}
+}
@@ -0,0 +1,251 @@
+package encoding
+
+import (
+	"fmt"
+	"math"
+)
+
+// Arch describes how the C ABI of a platform lays out types. The layout
+// of some types depends on the operating system as well as on the
+// architecture.
+type Arch struct {
+	Name            string
+	GOOS            string
+	GOARCH          string
+	PointerSize     int
+	LongLongAlign   int
+	DoubleAlign     int
+	LongDoubleSize  int
+	LongDoubleAlign int
+	HasInt128       bool
+}
+
+var (
+	DarwinAMD64 = &Arch{
+		Name:            "darwin/amd64",
+		GOOS:            "darwin",
+		GOARCH:          "amd64",
+		PointerSize:     8,
+		LongLongAlign:   8,
+		DoubleAlign:     8,
+		LongDoubleSize:  16,
+		LongDoubleAlign: 16,
+		HasInt128:       true,
+	}
+
+	// DarwinARM64 follows Apple's arm64 ABI, where long double is a
+	// double.
+	DarwinARM64 = &Arch{
+		Name:            "darwin/arm64",
+		GOOS:            "darwin",
+		GOARCH:          "arm64",
+		PointerSize:     8,
+		LongLongAlign:   8,
+		DoubleAlign:     8,
+		LongDoubleSize:  8,
+		LongDoubleAlign: 8,
+		HasInt128:       true,
+	}
+
+	// Darwin386 follows Apple's i386 ABI, where 64-bit values are 4 byte
+	// aligned within structs and long double takes 16 bytes.
+	Darwin386 = &Arch{
+		Name:            "darwin/386",
+		GOOS:            "darwin",
+		GOARCH:          "386",
+		PointerSize:     4,
+		LongLongAlign:   4,
+		DoubleAlign:     4,
+		LongDoubleSize:  16,
+		LongDoubleAlign: 16,
+	}
+
+	LinuxAMD64 = &Arch{
+		Name:            "linux/amd64",
+		GOOS:            "linux",
+		GOARCH:          "amd64",
+		PointerSize:     8,
+		LongLongAlign:   8,
+		DoubleAlign:     8,
+		LongDoubleSize:  16,
+		LongDoubleAlign: 16,
+		HasInt128:       true,
+	}
+
+	// LinuxARM64 follows the AAPCS64, where long double is a 128-bit
+	// quadruple precision float.
+	LinuxARM64 = &Arch{
+		Name:            "linux/arm64",
+		GOOS:            "linux",
+		GOARCH:          "arm64",
+		PointerSize:     8,
+		LongLongAlign:   8,
+		DoubleAlign:     8,
+		LongDoubleSize:  16,
+		LongDoubleAlign: 16,
+		HasInt128:       true,
+	}
+
+	// Linux386 follows the i386 System V ABI, where 64-bit values are 4
+	// byte aligned within structs and long double takes 12 bytes.
+	Linux386 = &Arch{
+		Name:            "linux/386",
+		GOOS:            "linux",
+		GOARCH:          "386",
+		PointerSize:     4,
+		LongLongAlign:   4,
+		DoubleAlign:     4,
+		LongDoubleSize:  12,
+		LongDoubleAlign: 4,
+	}
+
+	archs = []*Arch{DarwinAMD64, DarwinARM64, Darwin386, LinuxAMD64, LinuxARM64, Linux386}
+)
+
+// ArchOf returns the Arch of the platform identified by GOOS and GOARCH
+// values. It returns an error when the platform is not supported.
+func ArchOf(goos string, goarch string) (*Arch, error) {
+	for _, arch := range archs {
+		if arch.GOOS == goos && arch.GOARCH == goarch {
+			return arch, nil
+		}
+	}
+
+	return nil, fmt.Errorf("encoding: %s/%s is not supported", goos, goarch)
+}
+
+// SizeAlign parses the type encoding enc and returns the size and the
+// alignment in bytes of the type on arch.
+func SizeAlign(enc string, arch *Arch) (size int, align int, err error) {
+	t, err := Parse(enc)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return arch.SizeAlign(t)
+}
+
+// SizeAlign returns the size and the alignment in bytes of t. Runs of
+// bitfields are assumed to be stored in the smallest unsigned integer that
+// holds them, since encodings do not record their underlying type.
+func (arch *Arch) SizeAlign(t *Type) (size int, align int, err error) {
+	switch t.Kind {
+	case Char, UChar, Bool:
+		return 1, 1, nil
+
+	case Short, UShort:
+		return 2, 2, nil
+
+	case Int, UInt, Long, ULong, Float:
+		return 4, 4, nil
+
+	case LongLong, ULongLong:
+		return 8, arch.LongLongAlign, nil
+
+	case Double:
+		return 8, arch.DoubleAlign, nil
+
+	case LongDouble:
+		return arch.LongDoubleSize, arch.LongDoubleAlign, nil
+
+	case Int128, UInt128:
+		if arch.HasInt128 {
+			return 16, 16, nil
+		}
+
+	case CString, Object, Block, Class, Selector, Atom, Unknown, Pointer:
+		return arch.PointerSize, arch.PointerSize, nil
+
+	case Array:
+		if size, align, err = arch.SizeAlign(t.Elem); err != nil {
+			return 0, 0, err
+		}
+
+		if t.Len != 0 && size > math.MaxInt/t.Len {
+			return 0, 0, fmt.Errorf("encoding: %s is too large", t)
+		}
+
+		return size * t.Len, align, nil
+
+	case Complex:
+		if size, align, err = arch.SizeAlign(t.Elem); err != nil {
+			return 0, 0, err
+		}
+
+		return size * 2, align, nil
+
+	case Bitfield:
+		align = bitfieldSize(t.Len)
+		bits := align * 8
+		return (t.Len + bits - 1) / bits * align, align, nil
+
+	case Struct, Union:
+		if len(t.Fields) != 0 {
+			return arch.aggregateSizeAlign(t)
+		}
+
+		return 0, 0, fmt.Errorf("encoding: %s is opaque", t)
+	}
+
+	return 0, 0, fmt.Errorf("encoding: %s has no size on %s", t, arch.Name)
+}
+
+func (arch *Arch) aggregateSizeAlign(t *Type) (size int, align int, err error) {
+	align = 1
+
+	for i := 0; i < len(t.Fields); i++ {
+		field := t.Fields[i].Type
+
+		if field.Kind == Bitfield && t.Kind == Struct {
+			bits := 0
+
+			for ; i < len(t.Fields) && t.Fields[i].Type.Kind == Bitfield; i++ {
+				bits += t.Fields[i].Type.Len
+			}
+
+			i--
+			field = &Type{Kind: Bitfield, Len: bits}
+		}
+
+		fieldSize, fieldAlign, err := arch.SizeAlign(field)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if fieldAlign > align {
+			align = fieldAlign
+		}
+
+		if t.Kind == Union {
+			if fieldSize > size {
+				size = fieldSize
+			}
+
+			continue
+		}
+
+		if size = alignOffset(size, fieldAlign); size > math.MaxInt-fieldSize-align {
+			return 0, 0, fmt.Errorf("encoding: %s is too large", t)
+		}
+
+		size += fieldSize
+	}
+
+	return alignOffset(size, align), align, nil
+}
+
+func bitfieldSize(bits int) int {
+	size := 1
+
+	for size < 8 && size*8 < bits {
+		size *= 2
+	}
+
+	return size
+}
+
+func alignOffset(offset int, align int) int {
+	return (offset + align - 1) / align * align
+}
@@ -0,0 +1,100 @@
+package encoding
+
+import "testing"
+
+func TestSizeAlign(t *testing.T) {
+	tests := []struct {
+		enc      string
+		expected map[string][2]int
+	}{
+		{"c", map[string][2]int{"darwin/amd64": {1, 1}, "darwin/arm64": {1, 1}, "darwin/386": {1, 1}, "linux/amd64": {1, 1}, "linux/arm64": {1, 1}, "linux/386": {1, 1}}},
+		{"s", map[string][2]int{"darwin/amd64": {2, 2}, "darwin/arm64": {2, 2}, "darwin/386": {2, 2}, "linux/amd64": {2, 2}, "linux/arm64": {2, 2}, "linux/386": {2, 2}}},
+		{"l", map[string][2]int{"darwin/amd64": {4, 4}, "darwin/arm64": {4, 4}, "darwin/386": {4, 4}, "linux/amd64": {4, 4}, "linux/arm64": {4, 4}, "linux/386": {4, 4}}},
+		{"q", map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {8, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {8, 4}}},
+		{"d", map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {8, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {8, 4}}},
+		{"D", map[string][2]int{"darwin/amd64": {16, 16}, "darwin/arm64": {8, 8}, "darwin/386": {16, 16}, "linux/amd64": {16, 16}, "linux/arm64": {16, 16}, "linux/386": {12, 4}}},
+		{`@"NSString"`, map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {4, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {4, 4}}},
+		{"@?", map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {4, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {4, 4}}},
+		{"^{CGColor}", map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {4, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {4, 4}}},
+		{"[3s]", map[string][2]int{"darwin/amd64": {6, 2}, "darwin/arm64": {6, 2}, "darwin/386": {6, 2}, "linux/amd64": {6, 2}, "linux/arm64": {6, 2}, "linux/386": {6, 2}}},
+		{"jf", map[string][2]int{"darwin/amd64": {8, 4}, "darwin/arm64": {8, 4}, "darwin/386": {8, 4}, "linux/amd64": {8, 4}, "linux/arm64": {8, 4}, "linux/386": {8, 4}}},
+		{"{CGPoint=dd}", map[string][2]int{"darwin/amd64": {16, 8}, "darwin/arm64": {16, 8}, "darwin/386": {16, 4}, "linux/amd64": {16, 8}, "linux/arm64": {16, 8}, "linux/386": {16, 4}}},
+		{"{?=ci}", map[string][2]int{"darwin/amd64": {8, 4}, "darwin/arm64": {8, 4}, "darwin/386": {8, 4}, "linux/amd64": {8, 4}, "linux/arm64": {8, 4}, "linux/386": {8, 4}}},
+		{"{?=cqc}", map[string][2]int{"darwin/amd64": {24, 8}, "darwin/arm64": {24, 8}, "darwin/386": {16, 4}, "linux/amd64": {24, 8}, "linux/arm64": {24, 8}, "linux/386": {16, 4}}},
+		{"{?=c@}", map[string][2]int{"darwin/amd64": {16, 8}, "darwin/arm64": {16, 8}, "darwin/386": {8, 4}, "linux/amd64": {16, 8}, "linux/arm64": {16, 8}, "linux/386": {8, 4}}},
+		{"{?=[3c]s}", map[string][2]int{"darwin/amd64": {6, 2}, "darwin/arm64": {6, 2}, "darwin/386": {6, 2}, "linux/amd64": {6, 2}, "linux/arm64": {6, 2}, "linux/386": {6, 2}}},
+		{"{?=b1b7c}", map[string][2]int{"darwin/amd64": {2, 1}, "darwin/arm64": {2, 1}, "darwin/386": {2, 1}, "linux/amd64": {2, 1}, "linux/arm64": {2, 1}, "linux/386": {2, 1}}},
+		{"{?=b4b12i}", map[string][2]int{"darwin/amd64": {8, 4}, "darwin/arm64": {8, 4}, "darwin/386": {8, 4}, "linux/amd64": {8, 4}, "linux/arm64": {8, 4}, "linux/386": {8, 4}}},
+		{"(?=cdi)", map[string][2]int{"darwin/amd64": {8, 8}, "darwin/arm64": {8, 8}, "darwin/386": {8, 4}, "linux/amd64": {8, 8}, "linux/arm64": {8, 8}, "linux/386": {8, 4}}},
+		{"(?=[5c]s)", map[string][2]int{"darwin/amd64": {6, 2}, "darwin/arm64": {6, 2}, "darwin/386": {6, 2}, "linux/amd64": {6, 2}, "linux/arm64": {6, 2}, "linux/386": {6, 2}}},
+		{"T", map[string][2]int{"darwin/amd64": {16, 16}, "darwin/arm64": {16, 16}, "linux/amd64": {16, 16}, "linux/arm64": {16, 16}}},
+	}
+
+	for _, test := range tests {
+		for _, arch := range archs {
+			expected, ok := test.expected[arch.Name]
+			size, align, err := SizeAlign(test.enc, arch)
+
+			if !ok {
+				if err == nil {
+					t.Errorf("%s should have no size on %s", test.enc, arch.Name)
+				}
+
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("%s should have a size on %s: %v", test.enc, arch.Name, err)
+				continue
+			}
+
+			if size != expected[0] || align != expected[1] {
+				t.Errorf("%s should have a size of %d and an alignment of %d on %s: %d, %d", test.enc, expected[0], expected[1], arch.Name, size, align)
+			}
+		}
+	}
+}
+
+func TestSizeAlignError(t *testing.T) {
+	for _, enc := range []string{
+		"v",
+		"{CGColor}",
+		"{?=iv}",
+		"[2v]",
+		"x",
+		"[2147483646[2147483646[2147483646i]]]",
+		"{?=[2147483647[2147483647c]][2147483647[2147483647c]][2147483647[2147483647c]]}",
+	} {
+		if _, _, err := SizeAlign(enc, LinuxAMD64); err == nil {
+			t.Errorf("%s should have no size", enc)
+		}
+	}
+}
+
+func TestArchOf(t *testing.T) {
+	for _, platform := range [][2]string{
+		{"darwin", "amd64"},
+		{"darwin", "arm64"},
+		{"darwin", "386"},
+		{"linux", "amd64"},
+		{"linux", "arm64"},
+		{"linux", "386"},
+	} {
+		arch, err := ArchOf(platform[0], platform[1])
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if name := platform[0] + "/" + platform[1]; arch.Name != name {
+			t.Errorf("arch name should be %s: %s", name, arch.Name)
+		}
+	}
+
+	for _, platform := range [][2]string{{"linux", "arm"}, {"linux", "mips"}, {"windows", "amd64"}} {
+		if _, err := ArchOf(platform[0], platform[1]); err == nil {
+			t.Errorf("%s/%s should not be supported", platform[0], platform[1])
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package encoding
+
+import "fmt"
+
+// Compatible reports whether values of a and b are passed and returned the
+// same way by the C calling convention. Qualifiers, class names and the
+// signedness of integers are ignored; all pointers are compatible.
+func Compatible(a *Type, b *Type) error {
+	if !compatible(a, b) {
+		aName, bName := typeNames(a, b)
+		return fmt.Errorf("encoding: %s is not compatible with %s", aName, bName)
+	}
+
+	return nil
+}
+
+// Compatible reports whether an implementation of sig can be called
+// through other, and returns an error describing the first mismatch
+// otherwise.
+func (sig *MethodSignature) Compatible(other *MethodSignature) error {
+	if len(sig.Args) != len(other.Args) {
+		return fmt.Errorf("encoding: %s takes %d arguments, %s takes %d", sig, len(sig.Args), other, len(other.Args))
+	}
+
+	if !compatible(sig.Return, other.Return) {
+		ret, otherRet := typeNames(sig.Return, other.Return)
+		return fmt.Errorf("encoding: %s returns %s, %s returns %s", sig, ret, other, otherRet)
+	}
+
+	for i, arg := range sig.Args {
+		if !compatible(argType(arg.Type), argType(other.Args[i].Type)) {
+			argName, otherArgName := typeNames(arg.Type, other.Args[i].Type)
+			return fmt.Errorf("encoding: argument %d of %s is %s, argument %d of %s is %s", i, sig, argName, i, other, otherArgName)
+		}
+	}
+
+	return nil
+}
+
+func compatible(a *Type, b *Type) bool {
+	if a.IsPointer() || b.IsPointer() {
+		return a.IsPointer() && b.IsPointer()
+	}
+
+	if class := integerClass(a); class != 0 {
+		return class == integerClass(b)
+	}
+
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case Array:
+		return a.Len == b.Len && compatible(a.Elem, b.Elem)
+
+	case Complex:
+		return compatible(a.Elem, b.Elem)
+
+	case Bitfield:
+		return a.Len == b.Len
+
+	case Struct, Union:
+		if len(a.Fields) == 0 || len(b.Fields) == 0 {
+			return a.Name == b.Name
+		}
+
+		if len(a.Fields) != len(b.Fields) {
+			return false
+		}
+
+		for i := range a.Fields {
+			if !compatible(a.Fields[i].Type, b.Fields[i].Type) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// argType returns the type an argument of type t is passed as. Arrays
+// decay to pointers to their first element.
+func argType(t *Type) *Type {
+	if t.Kind == Array {
+		return &Type{Kind: Pointer, Elem: t.Elem}
+	}
+
+	return t
+}
+
+// typeNames returns the C names of a and b, or their encodings when the
+// names do not tell them apart.
+func typeNames(a *Type, b *Type) (string, string) {
+	if aName, bName := a.TypeName(), b.TypeName(); aName != bName {
+		return aName, bName
+	}
+
+	return a.String(), b.String()
+}
+
+// integerClass returns the size of the integer type t, or 0 when t is not
+// an integer. BOOL is a 1 byte integer in the calling convention.
+func integerClass(t *Type) int {
+	switch t.Kind {
+	case Char, UChar, Bool:
+		return 1
+
+	case Short, UShort:
+		return 2
+
+	case Int, UInt, Long, ULong:
+		return 4
+
+	case LongLong, ULongLong:
+		return 8
+
+	case Int128, UInt128:
+		return 16
+	}
+
+	return 0
+}
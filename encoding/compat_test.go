@@ -0,0 +1,88 @@
+package encoding
+
+import "testing"
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		compatible bool
+	}{
+		{"i", "I", true},
+		{"i", "l", true},
+		{"c", "B", true},
+		{"ri", "i", true},
+		{"q", "Q", true},
+		{"@", `@"NSString"`, true},
+		{"@", "#", true},
+		{"@?", "^v", true},
+		{"*", ":", true},
+		{"{CGPoint=dd}", `{NSPoint="x"d"y"d}`, true},
+		{"[4f]", "[4f]", true},
+		{"{CGColor}", "{CGColor}", true},
+		{"{CGColor}", "{CGColor=i}", true},
+		{"{CGRect}", "{CGRect={CGPoint=dd}{CGSize=dd}}", true},
+		{"^{CGRect}", "^{CGRect={CGPoint=dd}{CGSize=dd}}", true},
+		{"i", "q", false},
+		{"i", "f", false},
+		{"f", "d", false},
+		{"d", "D", false},
+		{"q", "@", false},
+		{"v", "i", false},
+		{"{CGPoint=dd}", "{CGSize=ff}", false},
+		{"{CGPoint=dd}", "{?=ddd}", false},
+		{"{CGPoint=dd}", "(?=dd)", false},
+		{"[4f]", "[3f]", false},
+		{"{CGColor}", "{CGPath}", false},
+	}
+
+	for _, test := range tests {
+		a, _ := Parse(test.a)
+		b, _ := Parse(test.b)
+
+		if err := Compatible(a, b); (err == nil) != test.compatible {
+			t.Errorf("compatibility of %s and %s should be %v: %v", test.a, test.b, test.compatible, err)
+		}
+
+		if err := Compatible(b, a); (err == nil) != test.compatible {
+			t.Errorf("compatibility of %s and %s should be %v: %v", test.b, test.a, test.compatible, err)
+		}
+	}
+}
+
+func TestMethodSignatureCompatible(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		compatible bool
+	}{
+		{"v16@0:8", "v@:", true},
+		{"c24@0:8@16", "B@:@", true},
+		{`@24@0:8@"NSString"16`, "@@:@", true},
+		{"v@:", "v@:i", false},
+		{"v@:i", "i@:i", false},
+		{"v@:i", "v@:d", false},
+		{"v@:[4i]", "v@:^i", true},
+		{"v@:[4i]", "v@:[2i]", true},
+		{"v@:[4i]", "v@:^d", true},
+		{"v@:[4i]", "v@:i", false},
+		{"v@:{CGRect}", "v@:{CGRect={CGPoint=dd}{CGSize=dd}}", true},
+	}
+
+	for _, test := range tests {
+		a, _ := ParseMethodSignature(test.a)
+		b, _ := ParseMethodSignature(test.b)
+
+		if err := a.Compatible(b); (err == nil) != test.compatible {
+			t.Errorf("compatibility of %s and %s should be %v: %v", test.a, test.b, test.compatible, err)
+		}
+	}
+}
+
+func TestCompatibleError(t *testing.T) {
+	a, _ := Parse("{CGRect=dd}")
+	b, _ := Parse("{CGRect=ff}")
+	err := Compatible(a, b)
+
+	if expected := "encoding: {CGRect=dd} is not compatible with {CGRect=ff}"; err == nil || err.Error() != expected {
+		t.Errorf("error should be %q: %v", expected, err)
+	}
+}
@@ -0,0 +1,252 @@
+package encoding
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var kindNames = map[Kind]string{
+	Char:       "char",
+	UChar:      "unsigned char",
+	Short:      "short",
+	UShort:     "unsigned short",
+	Int:        "int",
+	UInt:       "unsigned int",
+	Long:       "long",
+	ULong:      "unsigned long",
+	LongLong:   "long long",
+	ULongLong:  "unsigned long long",
+	Int128:     "__int128",
+	UInt128:    "unsigned __int128",
+	Float:      "float",
+	Double:     "double",
+	LongDouble: "long double",
+	Bool:       "BOOL",
+	Void:       "void",
+	Class:      "Class",
+	Selector:   "SEL",
+	Atom:       "NXAtom",
+	Unknown:    "void",
+}
+
+var qualifierNames = []string{"const", "in", "inout", "out", "bycopy", "byref", "oneway"}
+
+// TypeName returns the C or Objective-C name of t, such as "CGRect *",
+// "NSString *" or "void (^)(void)".
+func (t *Type) TypeName() string {
+	return t.Decl("")
+}
+
+// Decl returns the C or Objective-C declaration of a variable named name
+// of type t, such as "int name[4]". Named structs and unions are assumed to
+// be typedefs of the same name.
+func (t *Type) Decl(name string) string {
+	return t.qualifiers() + t.decl(name)
+}
+
+func (t *Type) qualifiers() string {
+	var s string
+
+	for i, qualifier := range qualifierNames {
+		if t.Qualifiers&(1<<uint(i)) != 0 {
+			s += qualifier + " "
+		}
+	}
+
+	return s
+}
+
+func (t *Type) decl(declarator string) string {
+	switch t.Kind {
+	case CString:
+		return declare("char", "*"+declarator)
+
+	case Object:
+		if t.Name == "" {
+			return declare("id"+protocolList(t.Protocols), declarator)
+		}
+
+		return declare(t.Name+protocolList(t.Protocols), "*"+declarator)
+
+	case Block:
+		return "void (^" + declarator + ")(void)"
+
+	case Pointer:
+		if t.Elem.Kind == Unknown {
+			return "void (*" + declarator + ")(void)"
+		}
+
+		return t.Elem.qualifiers() + t.Elem.decl("*"+declarator)
+
+	case Array:
+		if strings.HasPrefix(declarator, "*") {
+			declarator = "(" + declarator + ")"
+		}
+
+		return t.Elem.qualifiers() + t.Elem.decl(declarator+"["+strconv.Itoa(t.Len)+"]")
+
+	case Complex:
+		return "_Complex " + t.Elem.decl(declarator)
+
+	case Bitfield:
+		return declare("unsigned int", declarator) + " : " + strconv.Itoa(t.Len)
+
+	case Struct, Union:
+		return declare(t.aggregateName(), declarator)
+	}
+
+	return declare(kindNames[t.Kind], declarator)
+}
+
+func (t *Type) aggregateName() string {
+	keyword := "struct"
+
+	if t.Kind == Union {
+		keyword = "union"
+	}
+
+	if t.Name != "" && t.Name != "?" {
+		return t.Name
+	}
+
+	if len(t.Fields) == 0 {
+		return keyword
+	}
+
+	fields := make([]string, len(t.Fields))
+
+	for i, field := range t.Fields {
+		name := field.Name
+
+		if name == "" {
+			name = "field" + strconv.Itoa(i)
+		}
+
+		fields[i] = field.Type.Decl(name) + ";"
+	}
+
+	return keyword + " { " + strings.Join(fields, " ") + " }"
+}
+
+func declare(base string, declarator string) string {
+	if declarator == "" {
+		return base
+	}
+
+	return base + " " + declarator
+}
+
+func protocolList(protocols []string) string {
+	if len(protocols) == 0 {
+		return ""
+	}
+
+	return "<" + strings.Join(protocols, ", ") + ">"
+}
+
+// Decl returns the Objective-C declaration of the method named selector
+// with the signature sig, such as "- (void)setFoo:(int)foo;". The self and
+// _cmd arguments are omitted and parameter names are derived from the
+// selector.
+func (sig *MethodSignature) Decl(selector string, isClassMethod bool) string {
+	prefix := "- "
+
+	if isClassMethod {
+		prefix = "+ "
+	}
+
+	decl := prefix + "(" + sig.Return.TypeName() + ")"
+	var params []Arg
+
+	if len(sig.Args) > 2 {
+		params = sig.Args[2:]
+	}
+
+	labels := strings.Split(selector, ":")
+	labels = labels[:len(labels)-1]
+	names := sig.ParamNames(selector)
+
+	if len(labels) != len(params) {
+		decl += strings.TrimSuffix(selector, ":")
+
+		for i, param := range params {
+			decl += " :(" + param.Type.TypeName() + ")" + names[i]
+		}
+
+		return decl + ";"
+	}
+
+	if len(params) == 0 {
+		return decl + selector + ";"
+	}
+
+	for i, param := range params {
+		if i > 0 {
+			decl += " "
+		}
+
+		decl += labels[i] + ":(" + param.Type.TypeName() + ")" + names[i]
+	}
+
+	return decl + ";"
+}
+
+// ParamNames returns a name for each explicit argument of the method, after
+// self and _cmd, derived from the labels of selector. Arguments are named
+// arg0, arg1... when the labels do not match the arguments.
+func (sig *MethodSignature) ParamNames(selector string) []string {
+	var params []Arg
+
+	if len(sig.Args) > 2 {
+		params = sig.Args[2:]
+	}
+
+	labels := strings.Split(selector, ":")
+	labels = labels[:len(labels)-1]
+	names := make([]string, len(params))
+	used := map[string]bool{}
+
+	for i := range params {
+		name := "arg" + strconv.Itoa(i)
+
+		if len(labels) == len(params) {
+			name = paramName(labels[i], i)
+		}
+
+		if used[name] {
+			name += strconv.Itoa(i)
+		}
+
+		used[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// paramName derives a parameter name from the last word of a selector
+// label, such as "frame" from "initWithFrame" or "url" from "initWithURL".
+func paramName(label string, i int) string {
+	if label == "" {
+		return "arg" + strconv.Itoa(i)
+	}
+
+	start := strings.LastIndexFunc(label, unicode.IsUpper)
+
+	if start == len(label)-1 {
+		for start > 0 && unicode.IsUpper(rune(label[start-1])) {
+			start--
+		}
+
+		if start > 0 {
+			return strings.ToLower(label[start:])
+		}
+	}
+
+	if start <= 0 {
+		return label
+	}
+
+	return strings.ToLower(label[start:start+1]) + label[start+1:]
+}
@@ -0,0 +1,132 @@
+package encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		enc      string
+		expected string
+	}{
+		{"i", "int"},
+		{"Q", "unsigned long long"},
+		{"B", "BOOL"},
+		{"v", "void"},
+		{"Vv", "oneway void"},
+		{"r*", "const char *"},
+		{"@", "id"},
+		{`@"NSString"`, "NSString *"},
+		{`@"<NSCopying>"`, "id<NSCopying>"},
+		{`@"NSObject<NSCopying><NSCoding>"`, "NSObject<NSCopying, NSCoding> *"},
+		{"@?", "void (^)(void)"},
+		{"#", "Class"},
+		{":", "SEL"},
+		{"^v", "void *"},
+		{"^?", "void (*)(void)"},
+		{`^@"NSError"`, "NSError **"},
+		{"^{CGRect={CGPoint=dd}{CGSize=dd}}", "CGRect *"},
+		{"^^{__CFString=}", "__CFString **"},
+		{"[4f]", "float [4]"},
+		{"[4^i]", "int *[4]"},
+		{"^[4i]", "int (*)[4]"},
+		{"{?=ii}", "struct { int field0; int field1; }"},
+		{`(?="i"i"f"f)`, "union { int i; float f; }"},
+		{"jd", "_Complex double"},
+	}
+
+	for _, test := range tests {
+		typ, err := Parse(test.enc)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.enc, err)
+			continue
+		}
+
+		if name := typ.TypeName(); name != test.expected {
+			t.Errorf("%s should be named %q: %q", test.enc, test.expected, name)
+		}
+	}
+}
+
+func TestDecl(t *testing.T) {
+	tests := []struct {
+		enc      string
+		expected string
+	}{
+		{"i", "int count"},
+		{`@"NSString"`, "NSString *count"},
+		{"@?", "void (^count)(void)"},
+		{"^?", "void (*count)(void)"},
+		{"[4f]", "float count[4]"},
+		{"^[4f]", "float (*count)[4]"},
+		{"b3", "unsigned int count : 3"},
+	}
+
+	for _, test := range tests {
+		typ, _ := Parse(test.enc)
+
+		if decl := typ.Decl("count"); decl != test.expected {
+			t.Errorf("%s should be declared as %q: %q", test.enc, test.expected, decl)
+		}
+	}
+}
+
+func TestMethodSignatureDecl(t *testing.T) {
+	tests := []struct {
+		types         string
+		selector      string
+		isClassMethod bool
+		expected      string
+	}{
+		{"v20@0:8i16", "setFoo:", false, "- (void)setFoo:(int)foo;"},
+		{`@"NSString"16@0:8`, "description", false, "- (NSString *)description;"},
+		{"@16@0:8", "alloc", true, "+ (id)alloc;"},
+		{`@32@0:8{CGRect={CGPoint=dd}{CGSize=dd}}16`, "initWithFrame:", false, "- (id)initWithFrame:(CGRect)frame;"},
+		{"v40@0:8@16:24@32", "addObserver:selector:object:", false, "- (void)addObserver:(id)observer selector:(SEL)selector object:(id)object;"},
+		{"@24@0:8@16", "initWithURL:", false, "- (id)initWithURL:(id)url;"},
+		{"v24@0:8@16@24", "foo::", false, "- (void)foo:(id)foo :(id)arg1;"},
+		{"v24@0:8i16", "bar", false, "- (void)bar :(int)arg0;"},
+		{"Vv16@0:8", "release", false, "- (oneway void)release;"},
+	}
+
+	for _, test := range tests {
+		sig, err := ParseMethodSignature(test.types)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.types, err)
+			continue
+		}
+
+		if decl := sig.Decl(test.selector, test.isClassMethod); decl != test.expected {
+			t.Errorf("%s should be declared as %q: %q", test.selector, test.expected, decl)
+		}
+	}
+}
+
+func TestMethodSignatureParamNames(t *testing.T) {
+	tests := []struct {
+		types    string
+		selector string
+		expected string
+	}{
+		{"v@:", "description", ""},
+		{"v@:@q", "tableView:numberOfRowsInSection:", "view section"},
+		{"v@:@@", "foo:foo:", "foo foo1"},
+		{"v@:i", "bar", "arg0"},
+	}
+
+	for _, test := range tests {
+		sig, err := ParseMethodSignature(test.types)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.types, err)
+			continue
+		}
+
+		if names := strings.Join(sig.ParamNames(test.selector), " "); names != test.expected {
+			t.Errorf("%s parameters should be named %q: %q", test.selector, test.expected, names)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package encoding
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Encoder generates type encodings from Go types laid out like their C
+// counterparts.
+type Encoder struct {
+	// FieldNames includes the names of struct fields in the encodings.
+	FieldNames bool
+
+	// Types maps Go types to the types they are encoded as, such as the
+	// object and class types of a runtime binding.
+	Types map[reflect.Type]*Type
+}
+
+// Encode returns the type encoding of t, such as "{Point=dd}" for a struct
+// Point with two float64 fields.
+func Encode(t reflect.Type) (string, error) {
+	return (&Encoder{}).Encode(t)
+}
+
+// Encode returns the type encoding of t.
+func (e *Encoder) Encode(t reflect.Type) (string, error) {
+	typ, err := e.TypeOf(t)
+
+	if err != nil {
+		return "", err
+	}
+
+	return typ.String(), nil
+}
+
+// TypeOf returns the parsed type encoding of t. Strings are encoded as C
+// strings, which is only possible outside pointers, arrays and structs.
+// Structs referring to themselves through pointers are encoded as opaque
+// structs at the second level.
+func (e *Encoder) TypeOf(t reflect.Type) (*Type, error) {
+	return e.typeOf(t, false, map[reflect.Type]bool{})
+}
+
+func (e *Encoder) typeOf(t reflect.Type, nested bool, visiting map[reflect.Type]bool) (*Type, error) {
+	if typ, ok := e.Types[t]; ok {
+		return typ, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Type{Kind: Bool}, nil
+
+	case reflect.Int8:
+		return &Type{Kind: Char}, nil
+
+	case reflect.Uint8:
+		return &Type{Kind: UChar}, nil
+
+	case reflect.Int16:
+		return &Type{Kind: Short}, nil
+
+	case reflect.Uint16:
+		return &Type{Kind: UShort}, nil
+
+	case reflect.Int32:
+		return &Type{Kind: Int}, nil
+
+	case reflect.Uint32:
+		return &Type{Kind: UInt}, nil
+
+	case reflect.Int64:
+		return &Type{Kind: LongLong}, nil
+
+	case reflect.Uint64:
+		return &Type{Kind: ULongLong}, nil
+
+	case reflect.Int:
+		if t.Size() == 4 {
+			return &Type{Kind: Int}, nil
+		}
+
+		return &Type{Kind: LongLong}, nil
+
+	case reflect.Uint, reflect.Uintptr:
+		if t.Size() == 4 {
+			return &Type{Kind: UInt}, nil
+		}
+
+		return &Type{Kind: ULongLong}, nil
+
+	case reflect.Float32:
+		return &Type{Kind: Float}, nil
+
+	case reflect.Float64:
+		return &Type{Kind: Double}, nil
+
+	case reflect.String:
+		if !nested {
+			return &Type{Kind: CString}, nil
+		}
+
+		return nil, fmt.Errorf("encoding: cannot encode %s within a pointer, an array or a struct", t)
+
+	case reflect.UnsafePointer:
+		return &Type{Kind: Pointer, Elem: &Type{Kind: Void}}, nil
+
+	case reflect.Ptr:
+		if elem := t.Elem(); elem.Kind() == reflect.Struct && visiting[elem] {
+			return &Type{Kind: Pointer, Elem: &Type{Kind: Struct, Name: structName(elem)}}, nil
+		}
+
+		elem, err := e.typeOf(t.Elem(), true, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Type{Kind: Pointer, Elem: elem}, nil
+
+	case reflect.Array:
+		elem, err := e.typeOf(t.Elem(), true, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Type{Kind: Array, Len: t.Len(), Elem: elem}, nil
+
+	case reflect.Struct:
+		typ := &Type{Kind: Struct, Name: structName(t)}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldType, err := e.typeOf(field.Type, true, visiting)
+
+			if err != nil {
+				return nil, err
+			}
+
+			typ.Fields = append(typ.Fields, Field{Type: fieldType})
+
+			if e.FieldNames {
+				typ.Fields[i].Name = field.Name
+			}
+		}
+
+		return typ, nil
+	}
+
+	return nil, fmt.Errorf("encoding: cannot encode %s", t)
+}
+
+func structName(t reflect.Type) string {
+	if name := t.Name(); name != "" {
+		return name
+	}
+
+	return "?"
+}
@@ -0,0 +1,149 @@
+package encoding
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+type point struct {
+	X float64
+	Y float64
+}
+
+type node struct {
+	Value int32
+	Next  *node
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{false, "B"},
+		{int8(0), "c"},
+		{uint8(0), "C"},
+		{int16(0), "s"},
+		{uint16(0), "S"},
+		{int32(0), "i"},
+		{uint32(0), "I"},
+		{int64(0), "q"},
+		{uint64(0), "Q"},
+		{float32(0), "f"},
+		{float64(0), "d"},
+		{"", "*"},
+		{unsafe.Pointer(nil), "^v"},
+		{(*int32)(nil), "^i"},
+		{[4]float32{}, "[4f]"},
+		{point{}, "{point=dd}"},
+		{&point{}, "^{point=dd}"},
+		{struct{ A, B int8 }{}, "{?=cc}"},
+		{[2]point{}, "[2{point=dd}]"},
+		{node{}, "{node=i^{node}}"},
+		{struct{}{}, "{?}"},
+	}
+
+	for _, test := range tests {
+		typ := reflect.TypeOf(test.value)
+		enc, err := Encode(typ)
+
+		if err != nil {
+			t.Errorf("%s should be encoded: %v", typ, err)
+			continue
+		}
+
+		if enc != test.expected {
+			t.Errorf("%s should be encoded as %s: %s", typ, test.expected, enc)
+		}
+
+		if _, err = Parse(enc); err != nil {
+			t.Errorf("%s encoding should be parsed: %v", typ, err)
+		}
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	for _, value := range []interface{}{
+		[]int32{},
+		map[string]int32{},
+		make(chan int),
+		func() {},
+		(*string)(nil),
+		[2]string{},
+		struct{ Name string }{},
+	} {
+		if enc, err := Encode(reflect.TypeOf(value)); err == nil {
+			t.Errorf("%T should not be encoded: %s", value, enc)
+		}
+	}
+}
+
+func TestEncoderFieldNames(t *testing.T) {
+	e := &Encoder{FieldNames: true}
+	enc, err := e.Encode(reflect.TypeOf(point{}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enc != `{point="X"d"Y"d}` {
+		t.Errorf(`point should be encoded as {point="X"d"Y"d}: %s`, enc)
+	}
+}
+
+func TestEncoderTypes(t *testing.T) {
+	type object unsafe.Pointer
+
+	e := &Encoder{
+		Types: map[reflect.Type]*Type{
+			reflect.TypeOf(object(nil)): {Kind: Object},
+		},
+	}
+
+	enc, err := e.Encode(reflect.TypeOf(struct {
+		Owner object
+		Count int32
+	}{}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enc != "{?=@i}" {
+		t.Errorf("struct should be encoded as {?=@i}: %s", enc)
+	}
+}
+
+func TestEncodeSize(t *testing.T) {
+	type vertex struct {
+		Position [3]float32
+		Flag     bool
+		Weight   float64
+		Next     *vertex
+	}
+
+	typ := reflect.TypeOf(vertex{})
+	enc, err := Encode(typ)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arch, err := ArchOf(runtime.GOOS, runtime.GOARCH)
+
+	if err != nil {
+		t.Skip(err)
+	}
+
+	size, align, err := SizeAlign(enc, arch)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uintptr(size) != typ.Size() || align != typ.Align() {
+		t.Errorf("%s should have a size of %d and an alignment of %d: %d, %d", enc, typ.Size(), typ.Align(), size, align)
+	}
+}
@@ -0,0 +1,62 @@
+package encoding
+
+// MethodSignature is a parsed method type encoding such as "v16@0:8".
+type MethodSignature struct {
+	Return *Type
+	Args   []Arg
+
+	// FrameSize is the size of the argument frame, as encoded after the
+	// return type. It is 0 when the encoding has no offsets.
+	FrameSize int
+}
+
+// Arg is a method argument and its offset in the argument frame.
+type Arg struct {
+	Type   *Type
+	Offset int
+}
+
+// ParseMethodSignature parses a method type encoding, with or without
+// frame offsets.
+func ParseMethodSignature(s string) (*MethodSignature, error) {
+	p := parser{s: s}
+	ret, err := p.parse(false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &MethodSignature{Return: ret}
+	sig.FrameSize, _ = p.offset()
+
+	for !p.done() {
+		var arg Arg
+
+		if arg.Type, err = p.parse(false); err != nil {
+			return nil, err
+		}
+
+		arg.Offset, _ = p.offset()
+		sig.Args = append(sig.Args, arg)
+	}
+
+	return sig, nil
+}
+
+// String returns the encoding of sig without frame offsets, such as
+// "v@:".
+func (sig *MethodSignature) String() string {
+	b := sig.Return.appendEncoding(nil)
+
+	for _, arg := range sig.Args {
+		b = arg.Type.appendEncoding(b)
+	}
+
+	return string(b)
+}
+
+// Equal reports whether sig and other encode the same types, regardless of
+// their frame offsets.
+func (sig *MethodSignature) Equal(other *MethodSignature) bool {
+	return sig.String() == other.String()
+}
@@ -0,0 +1,107 @@
+package encoding
+
+import "testing"
+
+func TestParseMethodSignature(t *testing.T) {
+	sig, err := ParseMethodSignature("{CGSize=dd}40@0:8r*16^{CGPoint=dd}24Vv-32")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := sig.Return.String(); s != "{CGSize=dd}" {
+		t.Errorf("return type should be {CGSize=dd}: %s", s)
+	}
+
+	if sig.FrameSize != 40 {
+		t.Errorf("frame size should be 40: %d", sig.FrameSize)
+	}
+
+	expected := []struct {
+		types  string
+		offset int
+	}{
+		{"@", 0},
+		{":", 8},
+		{"r*", 16},
+		{"^{CGPoint=dd}", 24},
+		{"Vv", -32},
+	}
+
+	if len(sig.Args) != len(expected) {
+		t.Fatalf("%d arguments should be parsed: %d", len(expected), len(sig.Args))
+	}
+
+	for i, arg := range sig.Args {
+		if s := arg.Type.String(); s != expected[i].types {
+			t.Errorf("argument %d should be %s: %s", i, expected[i].types, s)
+		}
+
+		if arg.Offset != expected[i].offset {
+			t.Errorf("argument %d offset should be %d: %d", i, expected[i].offset, arg.Offset)
+		}
+	}
+}
+
+func TestParseMethodSignatureWithoutOffsets(t *testing.T) {
+	sig, err := ParseMethodSignature("v@:i")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sig.FrameSize != 0 {
+		t.Errorf("frame size should be 0: %d", sig.FrameSize)
+	}
+
+	if l := len(sig.Args); l != 3 {
+		t.Errorf("3 arguments should be parsed: %d", l)
+	}
+}
+
+func TestParseMethodSignatureError(t *testing.T) {
+	for _, types := range []string{"", "v16@0:8x", "v16@0:{"} {
+		if _, err := ParseMethodSignature(types); err == nil {
+			t.Errorf("%q should not be parsed", types)
+		}
+	}
+}
+
+func TestMethodSignatureString(t *testing.T) {
+	tests := []struct {
+		types    string
+		expected string
+	}{
+		{"v16@0:8", "v@:"},
+		{"v@:", "v@:"},
+		{"@24@0:8@\"NSString\"16", "@@:@\"NSString\""},
+		{"Vv20@0:8r^{CGPoint=dd}16", "Vv@:r^{CGPoint=dd}"},
+	}
+
+	for _, test := range tests {
+		sig, err := ParseMethodSignature(test.types)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.types, err)
+			continue
+		}
+
+		if s := sig.String(); s != test.expected {
+			t.Errorf("%s should be printed as %s: %s", test.types, test.expected, s)
+		}
+	}
+}
+
+func TestMethodSignatureEqual(t *testing.T) {
+	a, _ := ParseMethodSignature("v24@0:8i16")
+	b, _ := ParseMethodSignature("v@:i")
+	c, _ := ParseMethodSignature("v@:I")
+
+	if !a.Equal(b) {
+		t.Errorf("%s should equal %s", a, b)
+	}
+
+	if a.Equal(c) {
+		t.Errorf("%s should not equal %s", a, c)
+	}
+}
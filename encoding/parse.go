@@ -0,0 +1,294 @@
+package encoding
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError describes a malformed type encoding.
+type SyntaxError struct {
+	Encoding string
+	Offset   int
+	Msg      string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("encoding: bad type encoding %q at %d: %s", e.Encoding, e.Offset, e.Msg)
+}
+
+// Parse parses the encoding of a single type, such as "{CGPoint=dd}" or
+// "@\"NSString\"".
+func Parse(s string) (*Type, error) {
+	p := parser{s: s}
+	t, err := p.parse(false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, p.errorf("unexpected %q after type", p.s[p.i:])
+	}
+
+	return t, nil
+}
+
+// maxLen bounds array lengths and bitfield widths so that they fit an int
+// on every architecture.
+const maxLen = 1<<31 - 1
+
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) done() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *parser) peek(c byte) bool {
+	return !p.done() && p.s[p.i] == c
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{
+		Encoding: p.s,
+		Offset:   p.i,
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) offset() (n int, ok bool) {
+	negative := p.peek('-')
+
+	if negative {
+		p.i++
+	}
+
+	n, ok = p.number()
+
+	if negative {
+		n = -n
+	}
+
+	return
+}
+
+func (p *parser) number() (n int, ok bool) {
+	for ; !p.done() && p.s[p.i] >= '0' && p.s[p.i] <= '9'; p.i++ {
+		if n = n*10 + int(p.s[p.i]-'0'); n > maxLen {
+			n = maxLen
+		}
+
+		ok = true
+	}
+
+	return
+}
+
+func (p *parser) length(what string) (int, error) {
+	start := p.i
+	n, ok := p.number()
+
+	if !ok {
+		return 0, p.errorf("missing %s", what)
+	}
+
+	if n == maxLen {
+		p.i = start
+		return 0, p.errorf("%s too large", what)
+	}
+
+	return n, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	start := p.i + 1
+	end := strings.IndexByte(p.s[start:], '"')
+
+	if end < 0 {
+		return "", p.errorf("unterminated quoted name")
+	}
+
+	p.i = start + end + 1
+	return p.s[start : start+end], nil
+}
+
+// parse parses a type at the current position. inNamedFields tells whether
+// the type is a field of a struct or union whose fields are named, where a
+// quoted string after '@' may be the name of the next field rather than a
+// class name.
+func (p *parser) parse(inNamedFields bool) (*Type, error) {
+	t := &Type{}
+
+	for !p.done() {
+		i := strings.IndexByte(qualifierCodes, p.s[p.i])
+
+		if i < 0 {
+			break
+		}
+
+		t.Qualifiers |= 1 << uint(i)
+		p.i++
+	}
+
+	if p.done() {
+		return nil, p.errorf("unexpected end of encoding")
+	}
+
+	code := p.s[p.i]
+	kind, ok := codeKinds[code]
+
+	if !ok {
+		return nil, p.errorf("unknown type code %q", code)
+	}
+
+	t.Kind = kind
+	p.i++
+
+	var err error
+
+	switch kind {
+	case Object:
+		err = p.parseObject(t, inNamedFields)
+
+	case Pointer, Complex:
+		t.Elem, err = p.parse(false)
+
+	case Bitfield:
+		t.Len, err = p.length("bitfield width")
+
+	case Array:
+		if t.Len, err = p.length("array length"); err != nil {
+			return nil, err
+		}
+
+		if t.Elem, err = p.parse(false); err != nil {
+			return nil, err
+		}
+
+		if !p.peek(']') {
+			return nil, p.errorf("missing ]")
+		}
+
+		p.i++
+
+	case Struct, Union:
+		err = p.parseFields(t)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (p *parser) parseObject(t *Type, inNamedFields bool) error {
+	if p.peek('?') {
+		p.i++
+		t.Kind = Block
+		return nil
+	}
+
+	if !p.peek('"') {
+		return nil
+	}
+
+	if inNamedFields {
+		end := strings.IndexByte(p.s[p.i+1:], '"')
+
+		if end < 0 {
+			return nil
+		}
+
+		// The quoted string is a class name when it is followed by the
+		// name of the next field or by the end of the aggregate.
+		next := p.i + end + 2
+
+		if next < len(p.s) && strings.IndexByte("\"})", p.s[next]) < 0 {
+			return nil
+		}
+	}
+
+	name, err := p.quoted()
+
+	if err != nil {
+		return err
+	}
+
+	t.Name, t.Protocols = splitProtocols(name)
+	return nil
+}
+
+// splitProtocols splits a class name such as "NSObject<NSCopying>" into the
+// class name and the protocols it lists. Names that are not well formed are
+// returned as is.
+func splitProtocols(name string) (string, []string) {
+	start := strings.IndexByte(name, '<')
+
+	if start < 0 {
+		return name, nil
+	}
+
+	var protocols []string
+
+	for rest := name[start:]; rest != ""; {
+		end := strings.IndexByte(rest, '>')
+
+		if rest[0] != '<' || end < 0 {
+			return name, nil
+		}
+
+		protocols = append(protocols, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return name[:start], protocols
+}
+
+func (p *parser) parseFields(t *Type) error {
+	closing := byte('}')
+
+	if t.Kind == Union {
+		closing = ')'
+	}
+
+	nameEnd := strings.IndexAny(p.s[p.i:], "="+string(closing))
+
+	if nameEnd < 0 {
+		return p.errorf("missing %c", closing)
+	}
+
+	t.Name = p.s[p.i : p.i+nameEnd]
+	p.i += nameEnd
+
+	if p.peek('=') {
+		p.i++
+		named := p.peek('"')
+
+		for !p.done() && !p.peek(closing) {
+			var field Field
+			var err error
+
+			if p.peek('"') {
+				if field.Name, err = p.quoted(); err != nil {
+					return err
+				}
+			}
+
+			if field.Type, err = p.parse(named); err != nil {
+				return err
+			}
+
+			t.Fields = append(t.Fields, field)
+		}
+	}
+
+	if !p.peek(closing) {
+		return p.errorf("missing %c", closing)
+	}
+
+	p.i++
+	return nil
+}
@@ -0,0 +1,175 @@
+package encoding
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		enc  string
+		want *Type
+	}{
+		{"i", &Type{Kind: Int}},
+		{"Q", &Type{Kind: ULongLong}},
+		{"B", &Type{Kind: Bool}},
+		{"v", &Type{Kind: Void}},
+		{"r*", &Type{Kind: CString, Qualifiers: Const}},
+		{"Vv", &Type{Kind: Void, Qualifiers: Oneway}},
+		{"no@", &Type{Kind: Object, Qualifiers: In | Out}},
+		{"@", &Type{Kind: Object}},
+		{`@"NSString"`, &Type{Kind: Object, Name: "NSString"}},
+		{`@"NSObject<NSCopying><NSCoding>"`, &Type{Kind: Object, Name: "NSObject", Protocols: []string{"NSCopying", "NSCoding"}}},
+		{`@"<NSCopying>"`, &Type{Kind: Object, Protocols: []string{"NSCopying"}}},
+		{"@?", &Type{Kind: Block}},
+		{"#", &Type{Kind: Class}},
+		{":", &Type{Kind: Selector}},
+		{"^?", &Type{Kind: Pointer, Elem: &Type{Kind: Unknown}}},
+		{"^^i", &Type{Kind: Pointer, Elem: &Type{Kind: Pointer, Elem: &Type{Kind: Int}}}},
+		{"[12^f]", &Type{Kind: Array, Len: 12, Elem: &Type{Kind: Pointer, Elem: &Type{Kind: Float}}}},
+		{"b3", &Type{Kind: Bitfield, Len: 3}},
+		{"jd", &Type{Kind: Complex, Elem: &Type{Kind: Double}}},
+		{"{CGPoint=dd}", &Type{
+			Kind: Struct,
+			Name: "CGPoint",
+			Fields: []Field{
+				{Type: &Type{Kind: Double}},
+				{Type: &Type{Kind: Double}},
+			},
+		}},
+		{`{CGPoint="x"d"y"d}`, &Type{
+			Kind: Struct,
+			Name: "CGPoint",
+			Fields: []Field{
+				{Name: "x", Type: &Type{Kind: Double}},
+				{Name: "y", Type: &Type{Kind: Double}},
+			},
+		}},
+		{"^{__CFString=}", &Type{Kind: Pointer, Elem: &Type{Kind: Struct, Name: "__CFString"}}},
+		{"^{CGColor}", &Type{Kind: Pointer, Elem: &Type{Kind: Struct, Name: "CGColor"}}},
+		{"(?=iI)", &Type{
+			Kind: Union,
+			Name: "?",
+			Fields: []Field{
+				{Type: &Type{Kind: Int}},
+				{Type: &Type{Kind: UInt}},
+			},
+		}},
+		{"{?=b1b7c}", &Type{
+			Kind: Struct,
+			Name: "?",
+			Fields: []Field{
+				{Type: &Type{Kind: Bitfield, Len: 1}},
+				{Type: &Type{Kind: Bitfield, Len: 7}},
+				{Type: &Type{Kind: Char}},
+			},
+		}},
+		{`{S=@"NSString"i}`, &Type{
+			Kind: Struct,
+			Name: "S",
+			Fields: []Field{
+				{Type: &Type{Kind: Object, Name: "NSString"}},
+				{Type: &Type{Kind: Int}},
+			},
+		}},
+		{`{S="a"@"NSString""b"@"c"i}`, &Type{
+			Kind: Struct,
+			Name: "S",
+			Fields: []Field{
+				{Name: "a", Type: &Type{Kind: Object, Name: "NSString"}},
+				{Name: "b", Type: &Type{Kind: Object}},
+				{Name: "c", Type: &Type{Kind: Int}},
+			},
+		}},
+		{`{S="a"@"NSString"}`, &Type{
+			Kind: Struct,
+			Name: "S",
+			Fields: []Field{
+				{Name: "a", Type: &Type{Kind: Object, Name: "NSString"}},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		typ, err := Parse(test.enc)
+
+		if err != nil {
+			t.Errorf("%s should be parsed: %v", test.enc, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(typ, test.want) {
+			t.Errorf("%s should be parsed to %#v: %#v", test.enc, test.want, typ)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, enc := range []string{
+		"",
+		"r",
+		"x",
+		"ii",
+		"^",
+		"[4i",
+		"[i]",
+		"[99999999999i]",
+		"b",
+		"{CGPoint=dd",
+		"{CGPoint",
+		"(?=i}",
+		`@"NSString`,
+		`{S="a`,
+	} {
+		_, err := Parse(enc)
+
+		if err == nil {
+			t.Errorf("%q should not be parsed", enc)
+			continue
+		}
+
+		if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("error for %q should be a *SyntaxError: %T", enc, err)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, enc := range []string{
+		"v",
+		"r^{__CFString=}",
+		`@"NSObject<NSCopying>"`,
+		"@?",
+		"[4[2^?]]",
+		"{CGRect={CGPoint=dd}{CGSize=dd}}",
+		`{S="a"@"NSString""b"@"c"i}`,
+		"(?=b1b7ic)",
+		"jf",
+		"Vv40@0:8",
+	} {
+		f.Add(enc)
+	}
+
+	f.Fuzz(func(t *testing.T, enc string) {
+		typ, err := Parse(enc)
+
+		if err != nil {
+			return
+		}
+
+		s := typ.String()
+		again, err := Parse(s)
+
+		if err != nil {
+			t.Fatalf("%q printed from %q should be parsed: %v", s, enc, err)
+		}
+
+		if !reflect.DeepEqual(typ, again) {
+			t.Fatalf("%q printed from %q should be parsed to the same type", s, enc)
+		}
+
+		if s2 := again.String(); s2 != s {
+			t.Fatalf("%q should be printed as %q: %q", enc, s, s2)
+		}
+	})
+}
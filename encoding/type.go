@@ -0,0 +1,234 @@
+// Package encoding parses and prints Objective-C type encodings, as
+// returned by Method_getTypeEncoding, Ivar_getTypeEncoding or the "T"
+// property attribute.
+package encoding
+
+import "strconv"
+
+// Kind is the kind of an encoded type.
+type Kind int
+
+const (
+	Invalid Kind = iota
+	Char
+	UChar
+	Short
+	UShort
+	Int
+	UInt
+	Long
+	ULong
+	LongLong
+	ULongLong
+	Int128
+	UInt128
+	Float
+	Double
+	LongDouble
+	Bool
+	Void
+	CString
+	Object
+	Block
+	Class
+	Selector
+	Atom
+	Unknown
+	Pointer
+	Array
+	Struct
+	Union
+	Bitfield
+	Complex
+)
+
+var kindCodes = map[Kind]byte{
+	Char:       'c',
+	UChar:      'C',
+	Short:      's',
+	UShort:     'S',
+	Int:        'i',
+	UInt:       'I',
+	Long:       'l',
+	ULong:      'L',
+	LongLong:   'q',
+	ULongLong:  'Q',
+	Int128:     't',
+	UInt128:    'T',
+	Float:      'f',
+	Double:     'd',
+	LongDouble: 'D',
+	Bool:       'B',
+	Void:       'v',
+	CString:    '*',
+	Object:     '@',
+	Class:      '#',
+	Selector:   ':',
+	Atom:       '%',
+	Unknown:    '?',
+	Pointer:    '^',
+	Array:      '[',
+	Struct:     '{',
+	Union:      '(',
+	Bitfield:   'b',
+	Complex:    'j',
+}
+
+var codeKinds = map[byte]Kind{}
+
+func init() {
+	for kind, code := range kindCodes {
+		codeKinds[code] = kind
+	}
+}
+
+// Qualifier is a set of method type qualifiers.
+type Qualifier uint8
+
+const (
+	Const  Qualifier = 1 << iota // r
+	In                           // n
+	InOut                        // N
+	Out                          // o
+	Bycopy                       // O
+	Byref                        // R
+	Oneway                       // V
+)
+
+const qualifierCodes = "rnNoORV"
+
+// Type is a parsed type encoding.
+type Type struct {
+	Kind       Kind
+	Qualifiers Qualifier
+
+	// Name is the tag of a struct or a union, or the class name of an
+	// object.
+	Name string
+
+	// Protocols lists the protocols an object conforms to.
+	Protocols []string
+
+	// Elem is the element type of a pointer, an array or a complex.
+	Elem *Type
+
+	// Len is the length of an array or the width of a bitfield.
+	Len int
+
+	// Fields are the fields of a struct or a union. A struct or a union
+	// without fields is opaque.
+	Fields []Field
+}
+
+// Field is a struct or union field.
+type Field struct {
+	Name string
+	Type *Type
+}
+
+// IsInteger reports whether t is an integer type.
+func (t *Type) IsInteger() bool {
+	return t.Kind >= Char && t.Kind <= UInt128
+}
+
+// IsFloat reports whether t is a floating-point type.
+func (t *Type) IsFloat() bool {
+	return t.Kind >= Float && t.Kind <= LongDouble
+}
+
+// IsPointer reports whether values of t are pointers.
+func (t *Type) IsPointer() bool {
+	switch t.Kind {
+	case CString, Object, Block, Class, Selector, Atom, Unknown, Pointer:
+		return true
+	}
+
+	return false
+}
+
+// String returns the encoding of t, without frame offsets.
+func (t *Type) String() string {
+	return string(t.appendEncoding(nil))
+}
+
+func (t *Type) appendEncoding(b []byte) []byte {
+	for i := 0; i < len(qualifierCodes); i++ {
+		if t.Qualifiers&(1<<uint(i)) != 0 {
+			b = append(b, qualifierCodes[i])
+		}
+	}
+
+	switch t.Kind {
+	case Object:
+		b = append(b, '@')
+
+		if t.Name != "" || len(t.Protocols) != 0 {
+			b = append(b, '"')
+			b = append(b, t.Name...)
+
+			for _, protocol := range t.Protocols {
+				b = append(b, '<')
+				b = append(b, protocol...)
+				b = append(b, '>')
+			}
+
+			b = append(b, '"')
+		}
+
+	case Block:
+		b = append(b, "@?"...)
+
+	case Pointer, Complex:
+		b = append(b, kindCodes[t.Kind])
+		b = t.Elem.appendEncoding(b)
+
+	case Array:
+		b = append(b, '[')
+		b = strconv.AppendInt(b, int64(t.Len), 10)
+		b = t.Elem.appendEncoding(b)
+		b = append(b, ']')
+
+	case Bitfield:
+		b = append(b, 'b')
+		b = strconv.AppendInt(b, int64(t.Len), 10)
+
+	case Struct, Union:
+		closing := byte('}')
+
+		if t.Kind == Union {
+			closing = ')'
+		}
+
+		b = append(b, kindCodes[t.Kind])
+		b = append(b, t.Name...)
+
+		if len(t.Fields) != 0 {
+			b = append(b, '=')
+
+			// Once a field is named, every field is quoted so that a class
+			// name is never taken for the name of the next field.
+			named := false
+
+			for _, field := range t.Fields {
+				named = named || field.Name != ""
+			}
+
+			for _, field := range t.Fields {
+				if named {
+					b = append(b, '"')
+					b = append(b, field.Name...)
+					b = append(b, '"')
+				}
+
+				b = field.Type.appendEncoding(b)
+			}
+		}
+
+		b = append(b, closing)
+
+	default:
+		b = append(b, kindCodes[t.Kind])
+	}
+
+	return b
+}
@@ -0,0 +1,66 @@
+package encoding
+
+import "testing"
+
+func TestTypeString(t *testing.T) {
+	tests := []struct {
+		typ  *Type
+		want string
+	}{
+		{&Type{Kind: Int}, "i"},
+		{&Type{Kind: CString, Qualifiers: Const | In}, "rn*"},
+		{&Type{Kind: Object, Name: "NSString"}, `@"NSString"`},
+		{&Type{Kind: Object, Name: "NSObject", Protocols: []string{"NSCopying"}}, `@"NSObject<NSCopying>"`},
+		{&Type{Kind: Block}, "@?"},
+		{&Type{Kind: Array, Len: 3, Elem: &Type{Kind: Float}}, "[3f]"},
+		{&Type{Kind: Bitfield, Len: 5}, "b5"},
+		{&Type{Kind: Pointer, Elem: &Type{Kind: Struct, Name: "CGColor"}}, "^{CGColor}"},
+		{&Type{
+			Kind: Struct,
+			Name: "CGPoint",
+			Fields: []Field{
+				{Name: "x", Type: &Type{Kind: Double}},
+				{Name: "y", Type: &Type{Kind: Double}},
+			},
+		}, `{CGPoint="x"d"y"d}`},
+		{&Type{
+			Kind: Union,
+			Fields: []Field{
+				{Type: &Type{Kind: Int}},
+				{Type: &Type{Kind: Float}},
+			},
+		}, "(=if)"},
+	}
+
+	for _, test := range tests {
+		if s := test.typ.String(); s != test.want {
+			t.Errorf("type should be printed as %s: %s", test.want, s)
+		}
+	}
+}
+
+func TestTypePredicates(t *testing.T) {
+	for _, enc := range []string{"c", "C", "s", "S", "i", "I", "l", "L", "q", "Q", "t", "T"} {
+		if typ, _ := Parse(enc); !typ.IsInteger() || typ.IsFloat() || typ.IsPointer() {
+			t.Errorf("%s should only be an integer", enc)
+		}
+	}
+
+	for _, enc := range []string{"f", "d", "D"} {
+		if typ, _ := Parse(enc); typ.IsInteger() || !typ.IsFloat() || typ.IsPointer() {
+			t.Errorf("%s should only be a float", enc)
+		}
+	}
+
+	for _, enc := range []string{"*", "@", "@?", "#", ":", "%", "?", "^v"} {
+		if typ, _ := Parse(enc); typ.IsInteger() || typ.IsFloat() || !typ.IsPointer() {
+			t.Errorf("%s should only be a pointer", enc)
+		}
+	}
+
+	for _, enc := range []string{"v", "B", "[2i]", "{S=i}", "(U=i)", "b1", "jf"} {
+		if typ, _ := Parse(enc); typ.IsInteger() || typ.IsFloat() || typ.IsPointer() {
+			t.Errorf("%s should not be an integer, a float or a pointer", enc)
+		}
+	}
+}
@@ -0,0 +1,775 @@
+package objc
+
+// #include <stdlib.h>
+// #ifdef __APPLE__
+// #include <ffi/ffi.h>
+// #else
+// #include <ffi.h>
+// #endif
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// signature is a method type encoding prepared for libffi calls.
+type signature struct {
+	types      string
+	ret        *encoding.Type
+	args       []*encoding.Type
+	cif        *C.ffi_cif
+	argSizes   []uintptr
+	argOffsets []uintptr
+	retSize    uintptr
+	retOffset  uintptr
+	frameSize  uintptr
+	dispatch   dispatchKind
+}
+
+var (
+	signatureMutex sync.Mutex
+	signatures     = map[string]*signature{}
+	ffiStructTypes = map[string]*C.ffi_type{}
+)
+
+func newSignature(types string) (*signature, error) {
+	signatureMutex.Lock()
+	defer signatureMutex.Unlock()
+
+	if sig, ok := signatures[types]; ok {
+		return sig, nil
+	}
+
+	methodSig, err := encoding.ParseMethodSignature(types)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &signature{
+		types: types,
+		ret:   methodSig.Return,
+	}
+
+	for _, arg := range methodSig.Args {
+		sig.args = append(sig.args, arg.Type)
+	}
+
+	if err = sig.prepare(); err != nil {
+		return nil, err
+	}
+
+	signatures[types] = sig
+	return sig, nil
+}
+
+func (sig *signature) prepare() error {
+	var argTypes **C.ffi_type
+
+	rtype, err := ffiType(sig.ret, true)
+
+	if err != nil {
+		return err
+	}
+
+	argCount := len(sig.args)
+
+	if argCount != 0 {
+		argTypes = (**C.ffi_type)(calloc(uint(argCount), unsafe.Sizeof(*argTypes)))
+
+		for i, elem := 0, argTypes; i < argCount; i++ {
+			if *elem, err = ffiType(sig.args[i], false); err != nil {
+				free(unsafe.Pointer(argTypes))
+				return err
+			}
+
+			elem = nextFFIType(elem)
+		}
+	}
+
+	sig.cif = (*C.ffi_cif)(calloc(1, unsafe.Sizeof(*sig.cif)))
+
+	if C.ffi_prep_cif(sig.cif, C.FFI_DEFAULT_ABI, C.uint(argCount), rtype, argTypes) != C.FFI_OK {
+		free(unsafe.Pointer(sig.cif))
+		free(unsafe.Pointer(argTypes))
+		return fmt.Errorf("objc: cannot prepare call for %s", sig.types)
+	}
+
+	// The frame holds the argument pointers libffi expects, followed by
+	// the argument values and the return value.
+	offset := uintptr(argCount) * unsafe.Sizeof(unsafe.Pointer(nil))
+	sig.argSizes = make([]uintptr, argCount)
+	sig.argOffsets = make([]uintptr, argCount)
+
+	for i, elem := 0, argTypes; i < argCount; i++ {
+		offset = alignOffset(offset, uintptr((*elem).alignment))
+		sig.argSizes[i] = uintptr((*elem).size)
+		sig.argOffsets[i] = offset
+		offset += sig.argSizes[i]
+		elem = nextFFIType(elem)
+	}
+
+	// libffi widens integral return values to a full ffi_arg.
+	sig.retSize = uintptr(rtype.size)
+	sig.dispatch = returnDispatch(sig.ret, sig.retSize)
+	retSize := sig.retSize
+
+	if argSize := unsafe.Sizeof(C.ffi_arg(0)); retSize < argSize {
+		retSize = argSize
+	}
+
+	sig.retOffset = alignOffset(offset, 16)
+	sig.frameSize = sig.retOffset + retSize
+	return nil
+}
+
+func (sig *signature) call(fn unsafe.Pointer, args []interface{}) (interface{}, error) {
+	frame, err := sig.invoke(fn, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer free(frame)
+	return getValue(sig.retPtr(frame), sig.ret), nil
+}
+
+// callInto performs the call and stores the return value into the value
+// pointed to by ret.
+func (sig *signature) callInto(ret interface{}, fn unsafe.Pointer, args []interface{}) error {
+	frame, err := sig.invoke(fn, args)
+
+	if err != nil {
+		return err
+	}
+
+	defer free(frame)
+	return storeValue(ret, sig.retPtr(frame), sig.ret, sig.retSize)
+}
+
+// invoke performs the call and returns the frame holding the return value
+// at sig.retOffset. The frame must be freed by the caller.
+func (sig *signature) invoke(fn unsafe.Pointer, args []interface{}) (unsafe.Pointer, error) {
+	var arena callArena
+
+	if len(args) != len(sig.args) {
+		return nil, fmt.Errorf("objc: %s expects %d arguments: %d given", sig.types, len(sig.args), len(args))
+	}
+
+	frame := sig.newFrame()
+	defer arena.release()
+
+	for i, arg := range args {
+		if err := sig.setArg(frame, i, arg, &arena); err != nil {
+			free(frame)
+			return nil, err
+		}
+	}
+
+	sig.ffiCall(fn, frame)
+	return frame, nil
+}
+
+// newFrame allocates a frame whose argument pointers are set up for
+// libffi. The frame must be freed by the caller.
+func (sig *signature) newFrame() unsafe.Pointer {
+	frame := calloc(1, sig.frameSize)
+
+	for i, elem := 0, (*unsafe.Pointer)(frame); i < len(sig.args); i++ {
+		*elem = sig.argPtr(frame, i)
+		elem = nextPointer(elem)
+	}
+
+	return frame
+}
+
+func (sig *signature) argPtr(frame unsafe.Pointer, i int) unsafe.Pointer {
+	return unsafe.Pointer(uintptr(frame) + sig.argOffsets[i])
+}
+
+func (sig *signature) retPtr(frame unsafe.Pointer) unsafe.Pointer {
+	return unsafe.Pointer(uintptr(frame) + sig.retOffset)
+}
+
+func (sig *signature) setArg(frame unsafe.Pointer, i int, arg interface{}, arena *callArena) error {
+	if err := setValue(sig.argPtr(frame, i), sig.args[i], sig.argSizes[i], arg, arena); err != nil {
+		return fmt.Errorf("objc: argument %d of %s: %v", i, sig.types, err)
+	}
+
+	return nil
+}
+
+func (sig *signature) ffiCall(fn unsafe.Pointer, frame unsafe.Pointer) {
+	C.ffi_call(sig.cif, (*[0]byte)(fn), sig.retPtr(frame), (*unsafe.Pointer)(frame))
+}
+
+// callArena keeps the memory referenced by call arguments alive until the
+// call returns.
+type callArena struct {
+	cstrings []unsafe.Pointer
+	pinner   runtime.Pinner
+}
+
+func (arena *callArena) release() {
+	for _, cstring := range arena.cstrings {
+		free(cstring)
+	}
+
+	arena.cstrings = arena.cstrings[:0]
+	arena.pinner.Unpin()
+}
+
+func ffiType(t *encoding.Type, isReturn bool) (*C.ffi_type, error) {
+	switch t.Kind {
+	case encoding.Char:
+		return &C.ffi_type_sint8, nil
+
+	case encoding.UChar, encoding.Bool:
+		return &C.ffi_type_uint8, nil
+
+	case encoding.Short:
+		return &C.ffi_type_sint16, nil
+
+	case encoding.UShort:
+		return &C.ffi_type_uint16, nil
+
+	case encoding.Int, encoding.Long:
+		return &C.ffi_type_sint32, nil
+
+	case encoding.UInt, encoding.ULong:
+		return &C.ffi_type_uint32, nil
+
+	case encoding.LongLong:
+		return &C.ffi_type_sint64, nil
+
+	case encoding.ULongLong:
+		return &C.ffi_type_uint64, nil
+
+	case encoding.Float:
+		return &C.ffi_type_float, nil
+
+	case encoding.Double:
+		return &C.ffi_type_double, nil
+
+	case encoding.Object, encoding.Block, encoding.Class, encoding.Selector, encoding.CString, encoding.Pointer, encoding.Unknown:
+		return &C.ffi_type_pointer, nil
+
+	case encoding.Array:
+		// C arrays decay to pointers when passed as arguments.
+		if !isReturn {
+			return &C.ffi_type_pointer, nil
+		}
+
+	case encoding.Void:
+		if isReturn {
+			return &C.ffi_type_void, nil
+		}
+
+	case encoding.Struct:
+		return ffiStructType(t)
+	}
+
+	return nil, fmt.Errorf("objc: unsupported type %s", t)
+}
+
+func ffiStructType(t *encoding.Type) (*C.ffi_type, error) {
+	var elements []*C.ffi_type
+
+	if st, ok := ffiStructTypes[t.String()]; ok {
+		return st, nil
+	}
+
+	for _, field := range t.Fields {
+		count, elemType := 1, field.Type
+
+		for elemType.Kind == encoding.Array {
+			count *= elemType.Len
+			elemType = elemType.Elem
+		}
+
+		fieldType, err := ffiType(elemType, false)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < count; i++ {
+			elements = append(elements, fieldType)
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("objc: unsupported empty struct %s", t)
+	}
+
+	st := (*C.ffi_type)(calloc(1, unsafe.Sizeof(C.ffi_type{})))
+	st._type = C.FFI_TYPE_STRUCT
+	st.elements = (**C.ffi_type)(calloc(uint(len(elements)+1), unsafe.Sizeof(st)))
+
+	for i, elem := 0, st.elements; i < len(elements); i++ {
+		*elem = elements[i]
+		elem = nextFFIType(elem)
+	}
+
+	ffiStructTypes[t.String()] = st
+	return st, nil
+}
+
+func setValue(ptr unsafe.Pointer, t *encoding.Type, size uintptr, v interface{}, arena *callArena) error {
+	switch t.Kind {
+	case encoding.Object, encoding.Block:
+		switch v := v.(type) {
+		case nil:
+			*(*Id)(ptr) = nil
+
+		case Id:
+			*(*Id)(ptr) = v
+
+		case Class:
+			*(*Id)(ptr) = Id(unsafe.Pointer(v))
+
+		case Protocol:
+			*(*Id)(ptr) = Id(unsafe.Pointer(v))
+
+		default:
+			return typeMismatch(t, v)
+		}
+
+	case encoding.Class:
+		switch v := v.(type) {
+		case nil:
+			*(*Class)(ptr) = nil
+
+		case Class:
+			*(*Class)(ptr) = v
+
+		default:
+			return typeMismatch(t, v)
+		}
+
+	case encoding.Selector:
+		switch v := v.(type) {
+		case nil:
+			*(*Sel)(ptr) = nil
+
+		case Sel:
+			*(*Sel)(ptr) = v
+
+		default:
+			return typeMismatch(t, v)
+		}
+
+	case encoding.CString:
+		switch v := v.(type) {
+		case nil:
+			*(*unsafe.Pointer)(ptr) = nil
+
+		case string:
+			cstring := unsafe.Pointer(C.CString(v))
+			arena.cstrings = append(arena.cstrings, cstring)
+			*(*unsafe.Pointer)(ptr) = cstring
+
+		default:
+			return typeMismatch(t, v)
+		}
+
+	case encoding.Pointer, encoding.Unknown, encoding.Array:
+		if v == nil {
+			*(*unsafe.Pointer)(ptr) = nil
+			return nil
+		}
+
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.Ptr, reflect.UnsafePointer:
+			arena.pinner.Pin(v)
+			*(*unsafe.Pointer)(ptr) = unsafe.Pointer(rv.Pointer())
+
+		case reflect.Uintptr:
+			*(*uintptr)(ptr) = uintptr(rv.Uint())
+
+		default:
+			return typeMismatch(t, v)
+		}
+
+	case encoding.Struct:
+		rv := reflect.ValueOf(v)
+
+		if rv.Kind() == reflect.Ptr {
+			rv = rv.Elem()
+		}
+
+		if rv.Kind() != reflect.Struct || rv.Type().Size() != size {
+			return typeMismatch(t, v)
+		}
+
+		copyValue := reflect.New(rv.Type())
+		copyValue.Elem().Set(rv)
+		copyBytes(ptr, unsafe.Pointer(copyValue.Pointer()), size)
+
+	default:
+		return setNumber(ptr, t, v)
+	}
+
+	return nil
+}
+
+func setNumber(ptr unsafe.Pointer, t *encoding.Type, v interface{}) error {
+	var i int64
+	var f float64
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			i, f = 1, 1
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = rv.Int()
+		f = float64(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i = int64(rv.Uint())
+		f = float64(rv.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		f = rv.Float()
+		i = int64(f)
+
+	default:
+		return typeMismatch(t, v)
+	}
+
+	switch t.Kind {
+	case encoding.Char:
+		*(*int8)(ptr) = int8(i)
+
+	case encoding.UChar, encoding.Bool:
+		*(*uint8)(ptr) = uint8(i)
+
+	case encoding.Short:
+		*(*int16)(ptr) = int16(i)
+
+	case encoding.UShort:
+		*(*uint16)(ptr) = uint16(i)
+
+	case encoding.Int, encoding.Long:
+		*(*int32)(ptr) = int32(i)
+
+	case encoding.UInt, encoding.ULong:
+		*(*uint32)(ptr) = uint32(i)
+
+	case encoding.LongLong:
+		*(*int64)(ptr) = i
+
+	case encoding.ULongLong:
+		*(*uint64)(ptr) = uint64(i)
+
+	case encoding.Float:
+		*(*float32)(ptr) = float32(f)
+
+	case encoding.Double:
+		*(*float64)(ptr) = f
+
+	default:
+		return typeMismatch(t, v)
+	}
+
+	return nil
+}
+
+// getValue converts the C value at ptr to its Go counterpart. Integral
+// values are read from the low bytes, which is where libffi stores widened
+// return values on little-endian architectures.
+func getValue(ptr unsafe.Pointer, t *encoding.Type) interface{} {
+	switch t.Kind {
+	case encoding.Char:
+		return *(*int8)(ptr)
+
+	case encoding.UChar:
+		return *(*uint8)(ptr)
+
+	case encoding.Bool:
+		return *(*uint8)(ptr) != 0
+
+	case encoding.Short:
+		return *(*int16)(ptr)
+
+	case encoding.UShort:
+		return *(*uint16)(ptr)
+
+	case encoding.Int, encoding.Long:
+		return *(*int32)(ptr)
+
+	case encoding.UInt, encoding.ULong:
+		return *(*uint32)(ptr)
+
+	case encoding.LongLong:
+		return *(*int64)(ptr)
+
+	case encoding.ULongLong:
+		return *(*uint64)(ptr)
+
+	case encoding.Float:
+		return *(*float32)(ptr)
+
+	case encoding.Double:
+		return *(*float64)(ptr)
+
+	case encoding.Object, encoding.Block:
+		return *(*Id)(ptr)
+
+	case encoding.Class:
+		return *(*Class)(ptr)
+
+	case encoding.Selector:
+		return *(*Sel)(ptr)
+
+	case encoding.CString:
+		if cstring := *(**C.char)(ptr); cstring != nil {
+			return C.GoString(cstring)
+		}
+
+		return ""
+
+	case encoding.Pointer, encoding.Unknown, encoding.Array:
+		return *(*unsafe.Pointer)(ptr)
+
+	case encoding.Struct:
+		value := reflect.New(goType(t))
+		copyBytes(unsafe.Pointer(value.Pointer()), ptr, value.Elem().Type().Size())
+		return value.Elem().Interface()
+	}
+
+	return nil
+}
+
+// storeValue converts the C value at ptr and stores it into the value
+// pointed to by dst. Structs are copied as is into Go structs of the same
+// size.
+func storeValue(dst interface{}, ptr unsafe.Pointer, t *encoding.Type, size uintptr) error {
+	rv := reflect.ValueOf(dst)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("objc: cannot store %s into %T: not a non-nil pointer", t, dst)
+	}
+
+	elem := rv.Elem()
+
+	if t.Kind == encoding.Struct {
+		if elem.Kind() != reflect.Struct || elem.Type().Size() != size {
+			return fmt.Errorf("objc: cannot store %s into %T: %d bytes expected", t, dst, size)
+		}
+
+		copyBytes(unsafe.Pointer(rv.Pointer()), ptr, size)
+		return nil
+	}
+
+	value := getValue(ptr, t)
+
+	if value == nil {
+		return fmt.Errorf("objc: cannot store %s into %T", t, dst)
+	}
+
+	// BOOL is encoded as a char on some architectures.
+	if elem.Kind() == reflect.Bool && (t.Kind == encoding.Char || t.Kind == encoding.UChar) {
+		elem.SetBool(*(*uint8)(ptr) != 0)
+		return nil
+	}
+
+	if pointer, ok := value.(unsafe.Pointer); ok {
+		switch elem.Kind() {
+		case reflect.Ptr:
+			elem.Set(reflect.NewAt(elem.Type().Elem(), pointer))
+			return nil
+
+		case reflect.UnsafePointer:
+			elem.SetPointer(pointer)
+			return nil
+
+		case reflect.Uintptr:
+			elem.SetUint(uint64(uintptr(pointer)))
+			return nil
+		}
+	}
+
+	if rvalue := reflect.ValueOf(value); convertible(rvalue.Type(), elem.Type()) {
+		elem.Set(rvalue.Convert(elem.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("objc: cannot store %s into %T", t, dst)
+}
+
+// convertible reports whether from values can be stored into to values.
+// Unlike reflect, numbers are not convertible to strings.
+func convertible(from reflect.Type, to reflect.Type) bool {
+	if to.Kind() == reflect.String && from.Kind() != reflect.String {
+		return false
+	}
+
+	return from.ConvertibleTo(to)
+}
+
+var integerKinds = []reflect.Kind{
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+}
+
+// checkType reports whether values of the C type ret are exchanged with Go
+// as values of type t. The empty interface accepts any value.
+func checkType(ret *encoding.Type, t reflect.Type) error {
+	var kinds []reflect.Kind
+
+	if t.Kind() == reflect.Interface {
+		if reflect.TypeOf((*interface{})(nil)).Elem().AssignableTo(t) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot use %s", t)
+	}
+
+	switch ret.Kind {
+	case encoding.Void:
+		if t.Kind() == reflect.Struct && t.Size() == 0 {
+			return nil
+		}
+
+	case encoding.Char, encoding.UChar:
+		if t.Kind() == reflect.Bool {
+			return nil
+		}
+
+		kinds = integerKinds
+
+	case encoding.Short, encoding.UShort, encoding.Int, encoding.UInt, encoding.Long, encoding.ULong, encoding.LongLong, encoding.ULongLong:
+		kinds = integerKinds
+
+	case encoding.Bool:
+		kinds = []reflect.Kind{reflect.Bool}
+
+	case encoding.Float:
+		kinds = []reflect.Kind{reflect.Float32}
+
+	case encoding.Double:
+		kinds = []reflect.Kind{reflect.Float64}
+
+	case encoding.Object, encoding.Block, encoding.Class, encoding.Selector:
+		if t.Kind() == reflect.Ptr && goType(ret).ConvertibleTo(t) {
+			return nil
+		}
+
+	case encoding.CString:
+		if t.Kind() == reflect.String {
+			return nil
+		}
+
+	case encoding.Pointer, encoding.Unknown, encoding.Array:
+		kinds = []reflect.Kind{reflect.UnsafePointer, reflect.Ptr, reflect.Uintptr}
+
+	case encoding.Struct:
+		kinds = []reflect.Kind{reflect.Struct}
+	}
+
+	for _, kind := range kinds {
+		if t.Kind() == kind && t.Size() == goType(ret).Size() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot use %s", t)
+}
+
+// goType returns the Go type laid out like the C type t.
+func goType(t *encoding.Type) reflect.Type {
+	switch t.Kind {
+	case encoding.Char:
+		return reflect.TypeOf(int8(0))
+
+	case encoding.UChar:
+		return reflect.TypeOf(uint8(0))
+
+	case encoding.Bool:
+		return reflect.TypeOf(false)
+
+	case encoding.Short:
+		return reflect.TypeOf(int16(0))
+
+	case encoding.UShort:
+		return reflect.TypeOf(uint16(0))
+
+	case encoding.Int, encoding.Long:
+		return reflect.TypeOf(int32(0))
+
+	case encoding.UInt, encoding.ULong:
+		return reflect.TypeOf(uint32(0))
+
+	case encoding.LongLong:
+		return reflect.TypeOf(int64(0))
+
+	case encoding.ULongLong:
+		return reflect.TypeOf(uint64(0))
+
+	case encoding.Float:
+		return reflect.TypeOf(float32(0))
+
+	case encoding.Double:
+		return reflect.TypeOf(float64(0))
+
+	case encoding.Object, encoding.Block:
+		return reflect.TypeOf(Id(nil))
+
+	case encoding.Class:
+		return reflect.TypeOf(Class(nil))
+
+	case encoding.Selector:
+		return reflect.TypeOf(Sel(nil))
+
+	case encoding.Array:
+		return reflect.ArrayOf(t.Len, goType(t.Elem))
+
+	case encoding.Struct:
+		fields := make([]reflect.StructField, len(t.Fields))
+
+		for i, field := range t.Fields {
+			fields[i] = reflect.StructField{
+				Name: fmt.Sprintf("F%d", i),
+				Type: goType(field.Type),
+			}
+		}
+
+		return reflect.StructOf(fields)
+	}
+
+	return reflect.TypeOf(unsafe.Pointer(nil))
+}
+
+func copyBytes(dst unsafe.Pointer, src unsafe.Pointer, size uintptr) {
+	copy(unsafe.Slice((*byte)(dst), size), unsafe.Slice((*byte)(src), size))
+}
+
+func typeMismatch(t *encoding.Type, v interface{}) error {
+	return fmt.Errorf("cannot use %T as %s", v, t)
+}
+
+func alignOffset(offset uintptr, alignment uintptr) uintptr {
+	if alignment == 0 {
+		return offset
+	}
+
+	return (offset + alignment - 1) &^ (alignment - 1)
+}
+
+func nextFFIType(list **C.ffi_type) **C.ffi_type {
+	ptr := uintptr(unsafe.Pointer(list)) + unsafe.Sizeof(*list)
+	return (**C.ffi_type)(unsafe.Pointer(ptr))
+}
+
+func nextPointer(list *unsafe.Pointer) *unsafe.Pointer {
+	ptr := uintptr(unsafe.Pointer(list)) + unsafe.Sizeof(*list)
+	return (*unsafe.Pointer)(unsafe.Pointer(ptr))
+}
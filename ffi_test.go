@@ -0,0 +1,179 @@
+package objc
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+func TestNewSignature(t *testing.T) {
+	sig, err := newSignature("d@:if")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := len(sig.args); l != 4 {
+		t.Fatalf("args len should be 4: %d", l)
+	}
+
+	if sig.frameSize <= sig.retOffset {
+		t.Errorf("frame size should be greater than %d: %d", sig.retOffset, sig.frameSize)
+	}
+
+	if cached, _ := newSignature("d@:if"); cached != sig {
+		t.Errorf("signature should be cached: %p != %p", cached, sig)
+	}
+}
+
+func TestNewSignatureUnsupported(t *testing.T) {
+	for _, types := range []string{"v@:v", "v@:(Value=id)", "v@:b3"} {
+		if _, err := newSignature(types); err == nil {
+			t.Errorf("signature %s should not be supported", types)
+		}
+	}
+}
+
+func TestSetGetValue(t *testing.T) {
+	var arena callArena
+	defer arena.release()
+
+	ptr := calloc(1, 16)
+	defer free(ptr)
+
+	tests := []struct {
+		types    string
+		value    interface{}
+		expected interface{}
+	}{
+		{"c", true, int8(1)},
+		{"i", 42, int32(42)},
+		{"Q", uint8(42), uint64(42)},
+		{"f", 4.5, float32(4.5)},
+		{"d", 42, float64(42)},
+		{"B", 1, true},
+		{"*", "hello", "hello"},
+		{"^v", nil, unsafe.Pointer(nil)},
+	}
+
+	for _, test := range tests {
+		ret, _ := encoding.Parse(test.types)
+
+		if err := setValue(ptr, ret, 8, test.value, &arena); err != nil {
+			t.Errorf("set %v as %s failed: %v", test.value, test.types, err)
+			continue
+		}
+
+		if value := getValue(ptr, ret); value != test.expected {
+			t.Errorf("value should be %#v: %#v", test.expected, value)
+		}
+	}
+}
+
+func TestSetValueMismatch(t *testing.T) {
+	var arena callArena
+	defer arena.release()
+
+	ptr := calloc(1, 16)
+	defer free(ptr)
+
+	tests := []struct {
+		types string
+		value interface{}
+	}{
+		{"i", "42"},
+		{"@", 42},
+		{":", "sel"},
+		{"{CGPoint=dd}", struct{ X float32 }{}},
+	}
+
+	for _, test := range tests {
+		ret, _ := encoding.Parse(test.types)
+
+		if err := setValue(ptr, ret, 16, test.value, &arena); err == nil {
+			t.Errorf("set %#v as %s should have failed", test.value, test.types)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	ret, _ := encoding.Parse("{CGRect={CGPoint=dd}{CGSize=dd}}")
+
+	if size := goType(ret).Size(); size != 32 {
+		t.Errorf("size should be 32: %d", size)
+	}
+
+	ret, _ = encoding.Parse("{Vertex=[3f]c}")
+
+	if size := goType(ret).Size(); size != 16 {
+		t.Errorf("size should be 16: %d", size)
+	}
+}
+
+func TestGetStructValue(t *testing.T) {
+	var arena callArena
+	defer arena.release()
+
+	type point struct {
+		X float64
+		Y float64
+	}
+
+	ptr := calloc(1, 16)
+	defer free(ptr)
+
+	ret, _ := encoding.Parse("{CGPoint=dd}")
+	setValue(ptr, ret, 16, point{X: 21, Y: 42}, &arena)
+
+	value := getValue(ptr, ret)
+	expected := struct {
+		F0 float64
+		F1 float64
+	}{21, 42}
+
+	if value != expected {
+		t.Errorf("value should be %#v: %#v", expected, value)
+	}
+}
+
+func TestStoreValue(t *testing.T) {
+	var arena callArena
+	var n int
+	var p struct{ X, Y float64 }
+
+	defer arena.release()
+
+	ptr := calloc(1, 16)
+	defer free(ptr)
+
+	ret, _ := encoding.Parse("i")
+	setValue(ptr, ret, 4, 42, &arena)
+
+	if err := storeValue(&n, ptr, ret, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 42 {
+		t.Errorf("n should be 42: %d", n)
+	}
+
+	ret, _ = encoding.Parse("{CGPoint=dd}")
+	setValue(ptr, ret, 16, struct{ X, Y float64 }{21, 42}, &arena)
+
+	if err := storeValue(&p, ptr, ret, 16); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.X != 21 || p.Y != 42 {
+		t.Errorf("p should be {21 42}: %v", p)
+	}
+
+	if err := storeValue(p, ptr, ret, 16); err == nil {
+		t.Error("storing into a non pointer should have failed")
+	}
+
+	if err := storeValue(&n, ptr, ret, 16); err == nil {
+		t.Error("storing a struct into an int should have failed")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/maxence-charriere/go-objcruntime
+
+go 1.21
@@ -0,0 +1,108 @@
+package objc
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+var (
+	idType       = reflect.TypeOf(Id(nil))
+	classType    = reflect.TypeOf(Class(nil))
+	selType      = reflect.TypeOf(Sel(nil))
+	protocolType = reflect.TypeOf(Protocol(nil))
+)
+
+var typeEncoder = &encoding.Encoder{
+	Types: map[reflect.Type]*encoding.Type{
+		idType:       {Kind: encoding.Object},
+		protocolType: {Kind: encoding.Object},
+		classType:    {Kind: encoding.Class},
+		selType:      {Kind: encoding.Selector},
+	},
+}
+
+// MethodTypeEncoding returns the method type encoding of fn, a function
+// whose first parameters are the receiver Id and the selector Sel. For
+// example, func(Id, Sel, int32) bool is encoded as "B@:i".
+func MethodTypeEncoding(fn interface{}) (string, error) {
+	fnType := reflect.TypeOf(fn)
+
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return "", fmt.Errorf("objc: cannot encode %T as a method", fn)
+	}
+
+	if fnType.NumIn() < 2 || fnType.In(0) != idType || fnType.In(1) != selType {
+		return "", fmt.Errorf("objc: %s does not take an Id and a Sel as first parameters", fnType)
+	}
+
+	if fnType.IsVariadic() || fnType.NumOut() > 1 {
+		return "", fmt.Errorf("objc: cannot encode %s as a method", fnType)
+	}
+
+	types := "v"
+
+	if fnType.NumOut() == 1 {
+		ret, err := typeEncoder.Encode(fnType.Out(0))
+
+		if err != nil {
+			return "", err
+		}
+
+		types = ret
+	}
+
+	for i := 0; i < fnType.NumIn(); i++ {
+		arg, err := typeEncoder.Encode(fnType.In(i))
+
+		if err != nil {
+			return "", err
+		}
+
+		types += arg
+	}
+
+	return types, nil
+}
+
+// Class_addGoMethod adds fn as the implementation of the method name of
+// cls. The method type encoding is inferred with MethodTypeEncoding.
+func Class_addGoMethod(cls Class, name Sel, fn interface{}) error {
+	types, err := MethodTypeEncoding(fn)
+
+	if err != nil {
+		return err
+	}
+
+	imp, err := NewImp(fn, types)
+
+	if err != nil {
+		return err
+	}
+
+	if !Class_addMethod(cls, name, imp, types) {
+		FreeImp(imp)
+		return fmt.Errorf("objc: cannot add %s to %s", Sel_getName(name), Class_getName(cls))
+	}
+
+	return nil
+}
+
+// Class_addGoIvar adds an instance variable laid out like the Go type t to
+// cls. The type encoding is generated with the Go types of this package
+// mapped to their runtime counterparts.
+func Class_addGoIvar(cls Class, name string, t reflect.Type) error {
+	types, err := typeEncoder.Encode(t)
+
+	if err != nil {
+		return err
+	}
+
+	if !Class_addIvar(cls, name, uint(t.Size()), uint8(bits.TrailingZeros(uint(t.Align()))), types) {
+		return fmt.Errorf("objc: cannot add %s to %s", name, Class_getName(cls))
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package objc
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestMethodTypeEncoding(t *testing.T) {
+	type point struct {
+		X float64
+		Y float64
+	}
+
+	tests := []struct {
+		fn    interface{}
+		types string
+	}{
+		{func(Id, Sel) {}, "v@:"},
+		{func(Id, Sel, int32) bool { return false }, "B@:i"},
+		{func(Id, Sel, int64, float32, float64) {}, "v@:qfd"},
+		{func(Id, Sel, Id, Class, Sel) Id { return nil }, "@@:@#:"},
+		{func(Id, Sel, unsafe.Pointer, *int32) {}, "v@:^v^i"},
+		{func(Id, Sel, point) point { return point{} }, "{point=dd}@:{point=dd}"},
+		{func(Id, Sel, [4]float32, string) {}, "v@:[4f]*"},
+	}
+
+	for _, test := range tests {
+		types, err := MethodTypeEncoding(test.fn)
+
+		if err != nil {
+			t.Errorf("encoding %T failed: %v", test.fn, err)
+			continue
+		}
+
+		if types != test.types {
+			t.Errorf("%T types should be %s: %s", test.fn, test.types, types)
+		}
+	}
+}
+
+func TestMethodTypeEncodingInvalid(t *testing.T) {
+	tests := []interface{}{
+		42,
+		func() {},
+		func(Sel, Id) {},
+		func(Id, Sel, []int32) {},
+		func(Id, Sel, map[string]int32) {},
+		func(Id, Sel) (int32, error) { return 0, nil },
+		func(Id, Sel, ...int32) {},
+		func(Id, Sel, struct{ Name string }) {},
+	}
+
+	for _, fn := range tests {
+		if types, err := MethodTypeEncoding(fn); err == nil {
+			t.Errorf("encoding %T should have failed: %s", fn, types)
+		}
+	}
+}
+
+func TestClassAddGoMethod(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassWithGoMethod", 0)
+	sel := Sel_registerName("multiply:by:")
+
+	err := Class_addGoMethod(class, sel, func(self Id, cmd Sel, a int32, b int32) int32 {
+		return a * b
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Objc_registerClassPair(class)
+	instance := Class_createInstance(class, 0)
+	method := Class_getInstanceMethod(class, sel)
+
+	if types := Method_getTypeEncoding(method); types != "i@:ii" {
+		t.Errorf("types should be i@:ii: %s", types)
+	}
+
+	ret, err := Objc_msgSend(instance, sel, 6, 7)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != int32(42) {
+		t.Errorf("ret should be 42: %#v", ret)
+	}
+}
+
+func TestClassAddExistingGoMethod(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassWithExistingGoMethod", 0)
+	sel := Sel_registerName("touch")
+
+	Class_addGoMethod(class, sel, func(self Id, cmd Sel) {})
+
+	if err := Class_addGoMethod(class, sel, func(self Id, cmd Sel) {}); err == nil {
+		t.Error("adding touch twice should have failed")
+	}
+}
+
+func TestClassAddGoIvar(t *testing.T) {
+	type vertex struct {
+		Position [3]float32
+		Owner    Id
+	}
+
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithGoIvar", 0)
+
+	if err := Class_addGoIvar(class, "vertex", reflect.TypeOf(vertex{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if types := Ivar_getTypeEncoding(Class_getInstanceVariable(class, "vertex")); types != "{vertex=[3f]@}" {
+		t.Errorf("vertex type encoding should be {vertex=[3f]@}: %s", types)
+	}
+
+	if err := Class_addGoIvar(class, "vertex", reflect.TypeOf(vertex{})); err == nil {
+		t.Error("adding vertex twice should have failed")
+	}
+
+	if err := Class_addGoIvar(class, "names", reflect.TypeOf([]string{})); err == nil {
+		t.Error("adding a slice should have failed")
+	}
+}
@@ -0,0 +1,294 @@
+package objc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// RegisterStruct registers the class name, a subclass of superclass, whose
+// instance variables mirror the fields of the Go struct v, or of the
+// struct v points to.
+//
+// Each field becomes an instance variable named after the field, with a
+// lowercase first letter. Fields must not hold Go pointers: only Id,
+// Class, Sel, Protocol and plain scalar, array and struct types are
+// allowed. The field tag `objc:"-"` hides a field from Objective-C while
+// keeping its storage for methods. The field tag `objc:"name,options"`
+// renames a field, and the options are:
+//
+//	property       expose the field as a synthesized property
+//	readonly       do not synthesize a setter
+//	nonatomic      synthesize nonatomic accessors
+//	retain         retain Id values in the setter
+//	copy           copy Id values in the setter
+//	weak           store Id values as weak references
+//	getter=name    use a custom getter name
+//	setter=name:   use a custom setter name
+//
+// Property instance variables are prefixed with an underscore, as with
+// @synthesize.
+//
+// Exported methods of *T whose first parameters are an Id and a Sel become
+// instance methods. Their receiver points to the instance variables of the
+// instance receiving the message. Underscores in method names separate
+// selector labels whose first letter is lowercased, as the reverse of
+// ProtocolMethodNames: SetTitle becomes setTitle: when it takes one
+// argument and Insert_AtIndex_ becomes insert:atIndex:.
+func RegisterStruct(superclass Class, name string, v interface{}) (Class, error) {
+	t := reflect.TypeOf(v)
+
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("objc: cannot register %T: not a struct", v)
+	}
+
+	layout := &structLayout{typ: t}
+	builder := NewClassBuilder(name, superclass)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, err := parseStructTag(field)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hasGoPointers(field.Type) {
+			return nil, fmt.Errorf("objc: cannot register %s: field %s holds Go pointers", t, field.Name)
+		}
+
+		if !tag.property {
+			layout.ivars = append(layout.ivars, tag.name)
+			builder.GoIvar(tag.name, field.Type)
+			continue
+		}
+
+		types, err := typeEncoder.Encode(field.Type)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tag.attrs.Type = types
+		tag.attrs.Ivar = "_" + tag.name
+		layout.ivars = append(layout.ivars, tag.attrs.Ivar)
+		builder.Property(tag.name, tag.attrs)
+	}
+
+	builder.step(layout.check)
+	ptrType := reflect.PtrTo(t)
+
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		fnType := method.Type
+
+		if fnType.NumIn() < 3 || fnType.In(1) != idType || fnType.In(2) != selType {
+			continue
+		}
+
+		sel, err := structMethodSelector(method.Name, fnType.NumIn()-3)
+
+		if err != nil {
+			return nil, err
+		}
+
+		builder.Method(Sel_registerName(sel), layout.method(method))
+	}
+
+	cls, err := builder.Register()
+
+	if err != nil {
+		return nil, err
+	}
+
+	layout.cls = cls
+	return cls, nil
+}
+
+// structLayout maps instances of a registered class to the Go struct
+// mirroring their instance variables.
+type structLayout struct {
+	typ    reflect.Type
+	ivars  []string
+	cls    Class
+	once   sync.Once
+	offset uintptr
+}
+
+// check ensures that the instance variables are laid out like the fields
+// of the struct.
+func (layout *structLayout) check(cls Class) error {
+	if len(layout.ivars) == 0 {
+		return nil
+	}
+
+	base := Ivar_getOffset(Class_getInstanceVariable(cls, layout.ivars[0]))
+
+	if uintptr(base)%uintptr(layout.typ.Align()) != 0 {
+		return fmt.Errorf("instance variables are not aligned like %s", layout.typ)
+	}
+
+	for i, name := range layout.ivars {
+		ivar := Class_getInstanceVariable(cls, name)
+
+		if ivar == nil || uintptr(Ivar_getOffset(ivar)-base) != layout.typ.Field(i).Offset {
+			return fmt.Errorf("instance variable %s is not laid out like %s.%s", name, layout.typ, layout.typ.Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+// receiver returns a pointer to the struct mirroring the instance
+// variables of self.
+func (layout *structLayout) receiver(self Id) reflect.Value {
+	layout.once.Do(func() {
+		if len(layout.ivars) != 0 {
+			layout.offset = uintptr(Ivar_getOffset(Class_getInstanceVariable(layout.cls, layout.ivars[0])))
+		}
+	})
+
+	return reflect.NewAt(layout.typ, unsafe.Add(unsafe.Pointer(self), layout.offset))
+}
+
+func (layout *structLayout) method(method reflect.Method) interface{} {
+	in := make([]reflect.Type, method.Type.NumIn()-1)
+
+	for i := range in {
+		in[i] = method.Type.In(i + 1)
+	}
+
+	out := make([]reflect.Type, method.Type.NumOut())
+
+	for i := range out {
+		out[i] = method.Type.Out(i)
+	}
+
+	fnType := reflect.FuncOf(in, out, false)
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		receiver := layout.receiver(args[0].Interface().(Id))
+		return method.Func.Call(append([]reflect.Value{receiver}, args...))
+	}).Interface()
+}
+
+type structTag struct {
+	name     string
+	property bool
+	attrs    PropertyAttributes
+}
+
+func parseStructTag(field reflect.StructField) (tag structTag, err error) {
+	value := field.Tag.Get("objc")
+
+	// Hidden fields keep their storage, which methods use, under a name
+	// that is not an Objective-C identifier.
+	if value == "-" {
+		tag.name = "." + field.Name
+		return tag, nil
+	}
+
+	options := strings.Split(value, ",")
+	tag.name = options[0]
+
+	if tag.name == "" {
+		tag.name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+	}
+
+	for _, option := range options[1:] {
+		switch {
+		case option == "property":
+			tag.property = true
+
+		case option == "readonly":
+			tag.attrs.ReadOnly = true
+
+		case option == "nonatomic":
+			tag.attrs.Nonatomic = true
+
+		case option == "retain":
+			tag.attrs.Retain = true
+
+		case option == "copy":
+			tag.attrs.Copy = true
+
+		case option == "weak":
+			tag.attrs.Weak = true
+
+		case strings.HasPrefix(option, "getter="):
+			tag.attrs.Getter = strings.TrimPrefix(option, "getter=")
+
+		case strings.HasPrefix(option, "setter="):
+			tag.attrs.Setter = strings.TrimPrefix(option, "setter=")
+
+		default:
+			return tag, fmt.Errorf("objc: unknown option %q in the tag of %s", option, field.Name)
+		}
+	}
+
+	if !tag.property && len(options) > 1 {
+		return tag, fmt.Errorf("objc: property options in the tag of %s, which is not a property", field.Name)
+	}
+
+	return tag, nil
+}
+
+// hasGoPointers reports whether values of t hold Go pointers, which must
+// not be stored in instance variables since the Go garbage collector does
+// not scan them. Id, Class, Sel and Protocol point to runtime memory.
+func hasGoPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+
+	case reflect.Ptr, reflect.UnsafePointer:
+		return t != idType && t != classType && t != selType && t != protocolType
+
+	case reflect.Array:
+		return hasGoPointers(t.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasGoPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+
+		return false
+
+	default:
+		return true
+	}
+}
+
+// structMethodSelector returns the selector of the Go method name taking
+// argc arguments besides the receiver Id and the Sel.
+func structMethodSelector(name string, argc int) (string, error) {
+	labels := strings.Split(name, "_")
+
+	for i, label := range labels {
+		if label != "" {
+			labels[i] = strings.ToLower(label[:1]) + label[1:]
+		}
+	}
+
+	sel := strings.Join(labels, ":")
+
+	if len(labels) == 1 && argc == 1 {
+		sel += ":"
+	}
+
+	if colons := strings.Count(sel, ":"); colons != argc {
+		return "", fmt.Errorf("objc: %s takes %d arguments but maps to %s", name, argc, sel)
+	}
+
+	return sel, nil
+}
@@ -0,0 +1,169 @@
+package objc
+
+import (
+	"testing"
+	"unsafe"
+)
+
+type testModel struct {
+	Count   int32
+	Ratio   float64 `objc:"ratio,property,nonatomic"`
+	Owner   Id      `objc:"owner,property,retain"`
+	Enabled bool    `objc:"enabled,property,getter=isEnabled"`
+	Flags   uint8   `objc:"rawFlags"`
+	Hits    int64   `objc:"-"`
+	Proto   Protocol
+}
+
+func (m *testModel) Increment(self Id, cmd Sel) int32 {
+	m.Count++
+	return m.Count
+}
+
+func (m *testModel) SetCountTo(self Id, cmd Sel, count int32) {
+	m.Count = count
+}
+
+func (m *testModel) Add_Times_(self Id, cmd Sel, n int32, times int32) {
+	m.Count += n * times
+	m.Hits++
+}
+
+func (m *testModel) HitCount(self Id, cmd Sel) int64 {
+	return m.Hits
+}
+
+func (m *testModel) String() string {
+	return "model"
+}
+
+func TestRegisterStruct(t *testing.T) {
+	cls, err := RegisterStruct(Objc_getClass("NSObject"), "ModelFromStruct", &testModel{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ivar := range []string{"count", "_ratio", "_owner", "_enabled", "rawFlags"} {
+		if Class_getInstanceVariable(cls, ivar) == nil {
+			t.Errorf("class should have the ivar %s", ivar)
+		}
+	}
+
+	for _, ivar := range []string{"-", "hits", "Hits"} {
+		if Class_getInstanceVariable(cls, ivar) != nil {
+			t.Errorf("class should not have the ivar %s", ivar)
+		}
+	}
+
+	if Class_getInstanceVariable(cls, "proto") == nil {
+		t.Error("class should have the ivar proto")
+	}
+
+	for _, property := range []string{"ratio", "owner", "enabled"} {
+		if Class_getProperty(cls, property) == nil {
+			t.Errorf("class should have the property %s", property)
+		}
+	}
+
+	obj := Class_createInstance(cls, 0)
+	Objc_msgSend(obj, Sel_registerName("setCountTo:"), int32(40))
+
+	if count, _ := Send[int32](obj, Sel_registerName("increment")); count != 41 {
+		t.Errorf("count should be 41: %d", count)
+	}
+
+	Objc_msgSend(obj, Sel_registerName("add:times:"), int32(1), int32(3))
+
+	if count, _ := Send[int32](obj, Sel_registerName("increment")); count != 45 {
+		t.Errorf("count should be 45: %d", count)
+	}
+
+	if hits, _ := Send[int64](obj, Sel_registerName("hitCount")); hits != 1 {
+		t.Errorf("hits should be 1: %d", hits)
+	}
+
+	Objc_msgSend(obj, Sel_registerName("setRatio:"), 0.5)
+
+	if ratio, _ := Send[float64](obj, Sel_registerName("ratio")); ratio != 0.5 {
+		t.Errorf("ratio should be 0.5: %v", ratio)
+	}
+
+	Objc_msgSend(obj, Sel_registerName("setEnabled:"), true)
+
+	if enabled, _ := Send[bool](obj, Sel_registerName("isEnabled")); !enabled {
+		t.Error("enabled should be true")
+	}
+
+	if Class_getInstanceMethod(cls, Sel_registerName("string")) != nil {
+		t.Error("String should not be a method")
+	}
+}
+
+func TestRegisterStructInvalid(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{"StructFromInt", 42},
+		{"StructWithString", struct{ Name string }{}},
+		{"StructWithSlice", struct{ Names []int32 }{}},
+		{"StructWithPointer", struct{ Next *int32 }{}},
+		{"StructWithUnsafePointer", struct{ Data unsafe.Pointer }{}},
+		{"StructWithNestedPointer", struct {
+			Items [2]struct{ Next *int32 }
+		}{}},
+		{"StructWithBadOption", struct {
+			Count int32 `objc:"count,property,strong"`
+		}{}},
+		{"StructWithIvarOption", struct {
+			Count int32 `objc:"count,copy"`
+		}{}},
+		{"StructWithCopiedInt", struct {
+			Count int32 `objc:"count,property,copy"`
+		}{}},
+	}
+
+	for _, test := range tests {
+		if _, err := RegisterStruct(nsObject, test.name, test.value); err == nil {
+			t.Errorf("registering %s should have failed", test.name)
+		}
+
+		if Objc_getClass(test.name) != nil {
+			t.Errorf("%s should not be registered", test.name)
+		}
+	}
+}
+
+func TestStructMethodSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		argc     int
+		expected string
+	}{
+		{"Increment", 0, "increment"},
+		{"SetTitle", 1, "setTitle:"},
+		{"Insert_AtIndex_", 2, "insert:atIndex:"},
+		{"Add_Times_", 2, "add:times:"},
+		{"Value_", 1, "value:"},
+	}
+
+	for _, test := range tests {
+		sel, err := structMethodSelector(test.name, test.argc)
+
+		if err != nil {
+			t.Errorf("%s should map to a selector: %v", test.name, err)
+			continue
+		}
+
+		if sel != test.expected {
+			t.Errorf("%s should map to %s: %s", test.name, test.expected, sel)
+		}
+	}
+
+	if sel, err := structMethodSelector("Move", 2); err == nil {
+		t.Errorf("Move with 2 arguments should not map to a selector: %s", sel)
+	}
+}
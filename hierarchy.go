@@ -0,0 +1,210 @@
+package objc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Hierarchy is a tree of classes linked by inheritance. It can be
+// marshalled to JSON as the list of its roots.
+type Hierarchy struct {
+	Roots []*ClassNode
+	nodes map[string]*ClassNode
+}
+
+// ClassNode is a class of a Hierarchy. Depth is the number of superclasses
+// between the class and its root class.
+type ClassNode struct {
+	Class    Class        `json:"-"`
+	Name     string       `json:"name"`
+	Depth    int          `json:"depth"`
+	Parent   *ClassNode   `json:"-"`
+	Children []*ClassNode `json:"children,omitempty"`
+}
+
+// ClassHierarchy returns the hierarchy of every registered class, as
+// listed by Objc_copyClassList.
+func ClassHierarchy() *Hierarchy {
+	return NewHierarchy(Objc_copyClassList())
+}
+
+// NewHierarchy returns the hierarchy of classes, including their
+// superclasses. Roots and children are sorted by name.
+func NewHierarchy(classes []Class) *Hierarchy {
+	h := &Hierarchy{nodes: map[string]*ClassNode{}}
+
+	for _, cls := range classes {
+		h.add(cls)
+	}
+
+	sortNodes(h.Roots)
+
+	for _, node := range h.nodes {
+		sortNodes(node.Children)
+	}
+
+	for _, root := range h.Roots {
+		root.Walk(func(node *ClassNode) bool {
+			if node.Parent != nil {
+				node.Depth = node.Parent.Depth + 1
+			}
+
+			return true
+		})
+	}
+
+	return h
+}
+
+func (h *Hierarchy) add(cls Class) *ClassNode {
+	if cls == nil {
+		return nil
+	}
+
+	name := Class_getName(cls)
+
+	if node, ok := h.nodes[name]; ok {
+		return node
+	}
+
+	node := &ClassNode{
+		Class: cls,
+		Name:  name,
+	}
+
+	h.nodes[name] = node
+
+	if node.Parent = h.add(Class_getSuperclass(cls)); node.Parent != nil {
+		node.Parent.Children = append(node.Parent.Children, node)
+	} else {
+		h.Roots = append(h.Roots, node)
+	}
+
+	return node
+}
+
+// Node returns the node of the class named name, or nil when the class is
+// not in the hierarchy.
+func (h *Hierarchy) Node(name string) *ClassNode {
+	return h.nodes[name]
+}
+
+// Subtree returns the hierarchy rooted at the class named name, or nil
+// when the class is not in the hierarchy. Its nodes are shared with h.
+func (h *Hierarchy) Subtree(name string) *Hierarchy {
+	root := h.Node(name)
+
+	if root == nil {
+		return nil
+	}
+
+	subtree := &Hierarchy{
+		Roots: []*ClassNode{root},
+		nodes: map[string]*ClassNode{},
+	}
+
+	root.Walk(func(node *ClassNode) bool {
+		subtree.nodes[node.Name] = node
+		return true
+	})
+
+	return subtree
+}
+
+// Subclasses returns the direct and indirect subclasses of the class named
+// name, depth first.
+func (h *Hierarchy) Subclasses(name string) []*ClassNode {
+	if node := h.Node(name); node != nil {
+		return node.Descendants()
+	}
+
+	return nil
+}
+
+// Walk calls fn for each class, depth first, parents before their
+// children. The children of a class are skipped when fn returns false.
+func (h *Hierarchy) Walk(fn func(node *ClassNode) bool) {
+	for _, root := range h.Roots {
+		root.Walk(fn)
+	}
+}
+
+// Len returns the number of classes in the hierarchy.
+func (h *Hierarchy) Len() int {
+	return len(h.nodes)
+}
+
+// WriteDOT writes the hierarchy as a Graphviz digraph, with an edge from
+// each class to its superclass when the superclass is in the hierarchy.
+func (h *Hierarchy) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprint(w, "digraph classes {\n\trankdir=BT;\n\tnode [shape=box];\n"); err != nil {
+		return err
+	}
+
+	var err error
+
+	h.Walk(func(node *ClassNode) bool {
+		if err != nil {
+			return false
+		}
+
+		if node.Parent == nil || h.nodes[node.Parent.Name] != node.Parent {
+			_, err = fmt.Fprintf(w, "\t%s;\n", strconv.Quote(node.Name))
+		} else {
+			_, err = fmt.Fprintf(w, "\t%s -> %s;\n", strconv.Quote(node.Name), strconv.Quote(node.Parent.Name))
+		}
+
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (h *Hierarchy) MarshalJSON() ([]byte, error) {
+	if h.Roots == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(h.Roots)
+}
+
+// Walk calls fn for node and its descendants, depth first, parents before
+// their children. The children of a class are skipped when fn returns
+// false.
+func (node *ClassNode) Walk(fn func(node *ClassNode) bool) {
+	if !fn(node) {
+		return
+	}
+
+	for _, child := range node.Children {
+		child.Walk(fn)
+	}
+}
+
+// Descendants returns the direct and indirect subclasses of the class,
+// depth first.
+func (node *ClassNode) Descendants() (descendants []*ClassNode) {
+	for _, child := range node.Children {
+		child.Walk(func(n *ClassNode) bool {
+			descendants = append(descendants, n)
+			return true
+		})
+	}
+
+	return
+}
+
+func sortNodes(nodes []*ClassNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
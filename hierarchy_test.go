@@ -0,0 +1,100 @@
+package objc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewHierarchy(t *testing.T) {
+	animal := Objc_allocateClassPair(Objc_getClass("NSObject"), "HierarchyAnimal", 0)
+	Objc_registerClassPair(animal)
+	dog := Objc_allocateClassPair(animal, "HierarchyDog", 0)
+	Objc_registerClassPair(dog)
+	cat := Objc_allocateClassPair(animal, "HierarchyCat", 0)
+	Objc_registerClassPair(cat)
+	puppy := Objc_allocateClassPair(dog, "HierarchyPuppy", 0)
+	Objc_registerClassPair(puppy)
+
+	h := NewHierarchy([]Class{puppy, cat, dog})
+
+	if l := h.Len(); l != 5 {
+		t.Errorf("hierarchy should have 5 classes: %d", l)
+	}
+
+	if len(h.Roots) != 1 || h.Roots[0].Name != "NSObject" {
+		t.Fatalf("hierarchy root should be NSObject: %v", h.Roots)
+	}
+
+	node := h.Node("HierarchyAnimal")
+
+	if node == nil {
+		t.Fatal("hierarchy should contain HierarchyAnimal")
+	}
+
+	if node.Depth != 1 {
+		t.Errorf("HierarchyAnimal depth should be 1: %d", node.Depth)
+	}
+
+	if len(node.Children) != 2 || node.Children[0].Name != "HierarchyCat" || node.Children[1].Name != "HierarchyDog" {
+		t.Errorf("HierarchyAnimal children should be HierarchyCat and HierarchyDog: %v", node.Children)
+	}
+
+	if puppy := h.Node("HierarchyPuppy"); puppy == nil || puppy.Depth != 3 || puppy.Parent.Name != "HierarchyDog" {
+		t.Errorf("HierarchyPuppy should be a subclass of HierarchyDog at depth 3: %+v", puppy)
+	}
+
+	var names []string
+
+	for _, node := range h.Subclasses("HierarchyAnimal") {
+		names = append(names, node.Name)
+	}
+
+	if s := strings.Join(names, " "); s != "HierarchyCat HierarchyDog HierarchyPuppy" {
+		t.Errorf("HierarchyAnimal subclasses should be HierarchyCat HierarchyDog HierarchyPuppy: %s", s)
+	}
+
+	if h.Node("HierarchyUnknown") != nil || h.Subtree("HierarchyUnknown") != nil {
+		t.Error("unknown class should not be found")
+	}
+}
+
+func TestHierarchyExport(t *testing.T) {
+	animal := Objc_allocateClassPair(Objc_getClass("NSObject"), "ExportedAnimal", 0)
+	Objc_registerClassPair(animal)
+	dog := Objc_allocateClassPair(animal, "ExportedDog", 0)
+	Objc_registerClassPair(dog)
+	puppy := Objc_allocateClassPair(dog, "ExportedPuppy", 0)
+	Objc_registerClassPair(puppy)
+
+	h := NewHierarchy([]Class{puppy}).Subtree("ExportedDog")
+
+	if l := h.Len(); l != 2 {
+		t.Errorf("subtree should have 2 classes: %d", l)
+	}
+
+	var dot bytes.Buffer
+
+	if err := h.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "digraph classes {\n\trankdir=BT;\n\tnode [shape=box];\n\t\"ExportedDog\";\n\t\"ExportedPuppy\" -> \"ExportedDog\";\n}\n"
+
+	if dot.String() != expected {
+		t.Errorf("DOT should be:\n%s\n%s", expected, dot.String())
+	}
+
+	data, err := json.Marshal(h)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `[{"name":"ExportedDog","depth":2,"children":[{"name":"ExportedPuppy","depth":3}]}]`
+
+	if string(data) != expected {
+		t.Errorf("JSON should be %s: %s", expected, data)
+	}
+}
@@ -0,0 +1,248 @@
+package objc
+
+// #include <stdint.h>
+// #ifdef __APPLE__
+// #include <ffi/ffi.h>
+// #else
+// #include <ffi.h>
+// #endif
+//
+// extern void goImpCallback(ffi_cif *cif, void *ret, void **args, void *userdata);
+//
+// static void *newClosure(ffi_cif *cif, uintptr_t handle, void **code) {
+//     ffi_closure *closure = ffi_closure_alloc(sizeof(ffi_closure), code);
+//
+//     if (closure == NULL) {
+//         return NULL;
+//     }
+//
+//     if (ffi_prep_closure_loc(closure, cif, goImpCallback, (void *)handle, *code) != FFI_OK) {
+//         ffi_closure_free(closure);
+//         return NULL;
+//     }
+//
+//     return closure;
+// }
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// goImp is a Go function exposed as a method implementation.
+type goImp struct {
+	fn      reflect.Value
+	sig     *signature
+	handle  cgo.Handle
+	closure unsafe.Pointer
+}
+
+var (
+	goImpMutex sync.Mutex
+	goImps     = map[uintptr]*goImp{}
+)
+
+// NewImp returns an implementation that calls fn, for use with
+// Class_addMethod and friends. types is the method type encoding: fn takes
+// the receiver, the selector and the method arguments, and returns the
+// method result if any. The implementation must be released with FreeImp
+// once it is no longer referenced.
+//
+// Pointer results must be unsafe.Pointer or uintptr values pointing to C
+// memory: Go pointers are not kept alive once fn returns. Since errors
+// cannot be reported to the Objective-C caller, the implementation panics
+// when an argument or the result cannot be converted, which can only
+// happen with interface{} parameters or results.
+func NewImp(fn interface{}, types string) (Imp, error) {
+	fnValue := reflect.ValueOf(fn)
+
+	if fnValue.Kind() != reflect.Func {
+		return nil, fmt.Errorf("objc: cannot use %T as an implementation", fn)
+	}
+
+	sig, err := newSignature(types)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkFunc(fnValue.Type(), sig); err != nil {
+		return nil, err
+	}
+
+	imp := &goImp{
+		fn:  fnValue,
+		sig: sig,
+	}
+
+	var code unsafe.Pointer
+
+	imp.handle = cgo.NewHandle(imp)
+	imp.closure = C.newClosure(sig.cif, C.uintptr_t(imp.handle), &code)
+
+	if imp.closure == nil {
+		imp.handle.Delete()
+		return nil, fmt.Errorf("objc: cannot allocate implementation for %s", types)
+	}
+
+	goImpMutex.Lock()
+	goImps[uintptr(code)] = imp
+	goImpMutex.Unlock()
+
+	return Imp(code), nil
+}
+
+// FreeImp releases an implementation returned by NewImp.
+func FreeImp(imp Imp) {
+	goImpMutex.Lock()
+	defer goImpMutex.Unlock()
+
+	code := uintptr(unsafe.Pointer(imp))
+
+	if goImp, ok := goImps[code]; ok {
+		delete(goImps, code)
+		goImp.handle.Delete()
+		C.ffi_closure_free(goImp.closure)
+	}
+}
+
+func checkFunc(fnType reflect.Type, sig *signature) error {
+	if fnType.IsVariadic() || fnType.NumIn() != len(sig.args) {
+		return fmt.Errorf("objc: %s takes %d arguments: %s", sig.types, len(sig.args), fnType)
+	}
+
+	for i, arg := range sig.args {
+		if err := checkType(arg, fnType.In(i)); err != nil {
+			return fmt.Errorf("objc: argument %d of %s: %v", i, sig.types, err)
+		}
+	}
+
+	switch {
+	case sig.ret.Kind == encoding.Void:
+		if fnType.NumOut() != 0 {
+			return fmt.Errorf("objc: %s returns nothing: %s", sig.types, fnType)
+		}
+
+	case sig.ret.Kind == encoding.CString:
+		return fmt.Errorf("objc: %s returns a C string: not supported", sig.types)
+
+	case fnType.NumOut() != 1:
+		return fmt.Errorf("objc: %s returns one value: %s", sig.types, fnType)
+
+	case isPointer(sig.ret) && fnType.Out(0).Kind() == reflect.Ptr:
+		return fmt.Errorf("objc: %s returns a pointer: use unsafe.Pointer or uintptr instead of %s", sig.types, fnType.Out(0))
+
+	default:
+		if err := checkType(sig.ret, fnType.Out(0)); err != nil {
+			return fmt.Errorf("objc: return value of %s: %v", sig.types, err)
+		}
+	}
+
+	return nil
+}
+
+func isPointer(t *encoding.Type) bool {
+	return t.Kind == encoding.Pointer || t.Kind == encoding.Unknown || t.Kind == encoding.Array
+}
+
+func (imp *goImp) call(ret unsafe.Pointer, args *unsafe.Pointer) {
+	var arena callArena
+
+	sig := imp.sig
+	fnType := imp.fn.Type()
+	in := make([]reflect.Value, len(sig.args))
+
+	for i, elem := 0, args; i < len(in); i++ {
+		in[i] = reflect.New(fnType.In(i))
+
+		if err := storeValue(in[i].Interface(), *elem, sig.args[i], sig.argSizes[i]); err != nil {
+			panic(fmt.Errorf("objc: argument %d of %s: %v", i, sig.types, err))
+		}
+
+		in[i] = in[i].Elem()
+		elem = nextPointer(elem)
+	}
+
+	out := imp.fn.Call(in)
+
+	if len(out) == 0 {
+		return
+	}
+
+	result := out[0].Interface()
+
+	if isPointer(sig.ret) && reflect.ValueOf(result).Kind() == reflect.Ptr {
+		panic(fmt.Errorf("objc: return value of %s: cannot return the Go pointer %T", sig.types, result))
+	}
+
+	defer arena.release()
+
+	if err := setValue(ret, sig.ret, sig.retSize, result, &arena); err != nil {
+		panic(fmt.Errorf("objc: return value of %s: %v", sig.types, err))
+	}
+
+	widenReturn(ret, sig.ret)
+}
+
+// widenReturn extends integral return values to a full ffi_arg as libffi
+// expects from closures.
+func widenReturn(ret unsafe.Pointer, t *encoding.Type) {
+	switch t.Kind {
+	case encoding.Char:
+		*(*C.ffi_sarg)(ret) = C.ffi_sarg(*(*int8)(ret))
+
+	case encoding.UChar, encoding.Bool:
+		*(*C.ffi_arg)(ret) = C.ffi_arg(*(*uint8)(ret))
+
+	case encoding.Short:
+		*(*C.ffi_sarg)(ret) = C.ffi_sarg(*(*int16)(ret))
+
+	case encoding.UShort:
+		*(*C.ffi_arg)(ret) = C.ffi_arg(*(*uint16)(ret))
+
+	case encoding.Int, encoding.Long:
+		*(*C.ffi_sarg)(ret) = C.ffi_sarg(*(*int32)(ret))
+
+	case encoding.UInt, encoding.ULong:
+		*(*C.ffi_arg)(ret) = C.ffi_arg(*(*uint32)(ret))
+	}
+}
+
+// Imp_call calls imp with obj, sel and args, marshalled according to the
+// method type encoding types, and returns the converted result. It is
+// typically used to call the original implementation returned by
+// Method_setImplementation or Class_replaceMethod.
+func Imp_call(imp Imp, obj Id, sel Sel, types string, args ...interface{}) (interface{}, error) {
+	if imp == nil {
+		return nil, fmt.Errorf("objc: cannot call nil implementation of %s", Sel_getName(sel))
+	}
+
+	sig, err := newSignature(types)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.call(unsafe.Pointer(imp), append([]interface{}{obj, sel}, args...))
+}
+
+// Imp_call_stret calls imp like Imp_call and stores the result into the
+// value pointed to by stretAddr.
+func Imp_call_stret(stretAddr interface{}, imp Imp, obj Id, sel Sel, types string, args ...interface{}) error {
+	if imp == nil {
+		return fmt.Errorf("objc: cannot call nil implementation of %s", Sel_getName(sel))
+	}
+
+	sig, err := newSignature(types)
+
+	if err != nil {
+		return err
+	}
+
+	return sig.callInto(stretAddr, unsafe.Pointer(imp), append([]interface{}{obj, sel}, args...))
+}
@@ -0,0 +1,161 @@
+package objc
+
+import "testing"
+
+func newTestImp(t *testing.T, fn interface{}, types string) Imp {
+	imp, err := NewImp(fn, types)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return imp
+}
+
+func TestImpCall(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+	sel := Sel_registerName("class")
+	method := Class_getInstanceMethod(nsObject, sel)
+	imp := Method_getImplementation(method)
+
+	ret, err := Imp_call(imp, instance, sel, Method_getTypeEncoding(method))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if class := ret.(Class); class != nsObject {
+		t.Errorf("class should be %p: %p", nsObject, class)
+	}
+}
+
+func TestImpCallWithArguments(t *testing.T) {
+	var equal bool
+
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+	sel := Sel_registerName("isEqual:")
+	method := Class_getInstanceMethod(nsObject, sel)
+	imp := Method_getImplementation(method)
+
+	if err := Imp_call_stret(&equal, imp, instance, sel, Method_getTypeEncoding(method), instance); err != nil {
+		t.Fatal(err)
+	}
+
+	if !equal {
+		t.Error("instance should be equal to itself")
+	}
+}
+
+func TestImpCallNil(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Imp_call(nil, instance, Sel_registerName("class"), "#@:"); err == nil {
+		t.Error("calling a nil imp should have failed")
+	}
+}
+
+func TestImpCallBadTypes(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+	sel := Sel_registerName("class")
+	imp := Method_getImplementation(Class_getInstanceMethod(nsObject, sel))
+
+	if _, err := Imp_call(imp, instance, sel, "#@:{"); err == nil {
+		t.Error("calling with bad types should have failed")
+	}
+}
+
+func TestNewImp(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassWithGoImp", 0)
+	sel := Sel_registerName("add:to:")
+
+	imp := newTestImp(t, func(self Id, cmd Sel, a int32, b int32) int32 { return a + b }, "i@:ii")
+	defer FreeImp(imp)
+
+	Class_addMethod(class, sel, imp, "i@:ii")
+	Objc_registerClassPair(class)
+	instance := Class_createInstance(class, 0)
+
+	ret, err := Objc_msgSend(instance, sel, 21, 21)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != int32(42) {
+		t.Errorf("ret should be 42: %#v", ret)
+	}
+}
+
+func TestNewImpReceivesSelfAndSelector(t *testing.T) {
+	var receivedSelf Id
+	var receivedSel Sel
+
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassWithGoImpReceiver", 0)
+	sel := Sel_registerName("touch")
+
+	imp := newTestImp(t, func(self Id, cmd Sel) {
+		receivedSelf = self
+		receivedSel = cmd
+	}, "v@:")
+	defer FreeImp(imp)
+
+	Class_addMethod(class, sel, imp, "v@:")
+	Objc_registerClassPair(class)
+	instance := Class_createInstance(class, 0)
+
+	if _, err := Objc_msgSend(instance, sel); err != nil {
+		t.Fatal(err)
+	}
+
+	if receivedSelf != instance {
+		t.Errorf("self should be %p: %p", instance, receivedSelf)
+	}
+
+	if !Sel_isEqual(receivedSel, sel) {
+		t.Errorf("cmd should be %s: %s", Sel_getName(sel), Sel_getName(receivedSel))
+	}
+}
+
+func TestNewImpBool(t *testing.T) {
+	imp := newTestImp(t, func(self Id, cmd Sel, flag bool) bool { return !flag }, "c@:c")
+	defer FreeImp(imp)
+
+	ret, err := Imp_call(imp, nil, Sel_registerName("not:"), "c@:c", true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != int8(0) {
+		t.Errorf("ret should be 0: %#v", ret)
+	}
+}
+
+func TestNewImpMismatch(t *testing.T) {
+	tests := []struct {
+		fn    interface{}
+		types string
+	}{
+		{42, "v@:"},
+		{func(self Id) {}, "v@:"},
+		{func(self Id, cmd Sel) int32 { return 0 }, "v@:"},
+		{func(self Id, cmd Sel) {}, "i@:"},
+		{func(self Id, cmd Sel, n float64) {}, "v@:i"},
+		{func(self Id, cmd Sel) string { return "" }, "*@:"},
+		{func(self Id, cmd Sel, args ...interface{}) {}, "v@:"},
+		{func(self Id, cmd Sel) *int32 { return nil }, "^i@:"},
+	}
+
+	for _, test := range tests {
+		if imp, err := NewImp(test.fn, test.types); err == nil {
+			FreeImp(imp)
+			t.Errorf("%T should not implement %s", test.fn, test.types)
+		}
+	}
+}
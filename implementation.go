@@ -0,0 +1,64 @@
+package objc
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// RegisterImplementation registers the class name, a subclass of
+// superclass adopting protocols, whose instance methods are implemented by
+// the methods of impl.
+//
+// Each instance method of the protocols, and of the protocols they adopt,
+// is implemented by the method of impl named as described in
+// ProtocolMethodNames, which receives self and _cmd first, as in the
+// interfaces generated by GenerateProtocolInterface. All the instances of
+// the class share impl. Methods missing from impl are left to the
+// superclass, and the registration fails when a required method is
+// implemented by neither of them.
+func RegisterImplementation(name string, superclass Class, impl interface{}, protocols ...Protocol) (Class, error) {
+	if impl == nil {
+		return nil, fmt.Errorf("objc: cannot register %s: nil implementation", name)
+	}
+
+	value := reflect.ValueOf(impl)
+	builder := NewClassBuilder(name, superclass).VerifyConformance()
+
+	for _, proto := range protocols {
+		builder.Protocol(proto)
+
+		if proto == nil {
+			continue
+		}
+
+		for _, method := range ProtocolMethods(proto) {
+			if fn, ok := implementationMethod(value, method); ok {
+				builder.MethodWithTypes(Sel_registerName(method.Selector), fn, method.Types)
+			}
+		}
+	}
+
+	return builder.Register()
+}
+
+// implementationMethod returns the method of impl implementing method,
+// which takes self, _cmd and the arguments of method.
+func implementationMethod(impl reflect.Value, method ProtocolMethod) (interface{}, bool) {
+	sig, err := encoding.ParseMethodSignature(method.Types)
+
+	if err != nil {
+		return nil, false
+	}
+
+	for _, name := range ProtocolMethodNames(method.Selector) {
+		fn := impl.MethodByName(name)
+
+		if fn.IsValid() && fn.Type().NumIn() == len(sig.Args) {
+			return fn.Interface(), true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,58 @@
+package objc
+
+import "testing"
+
+type tableDelegate struct {
+	selected int64
+}
+
+func (d *tableDelegate) TableView_DidSelectRow_(self Id, cmd Sel, view Id, row int64) {
+	d.selected = row
+}
+
+func (d *tableDelegate) ShouldClose(self Id, cmd Sel) bool {
+	return true
+}
+
+func TestRegisterImplementation(t *testing.T) {
+	proto := Objc_allocateProtocol("ImplementedProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("tableView:didSelectRow:"), "v@:@q", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("shouldClose"), "c@:", false, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("willClose"), "v@:", false, true)
+	Objc_registerProtocol(proto)
+
+	delegate := &tableDelegate{}
+	cls, err := RegisterImplementation("ImplementedClass", Objc_getClass("NSObject"), delegate, proto)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !Class_conformsToProtocol(cls, proto) {
+		t.Error("class should conform to ImplementedProto")
+	}
+
+	obj := Class_createInstance(cls, 0)
+
+	if _, err = Send[struct{}](obj, Sel_registerName("tableView:didSelectRow:"), Id(nil), int64(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if delegate.selected != 3 {
+		t.Errorf("selected row should be 3: %d", delegate.selected)
+	}
+
+	if Class_respondsToSelector(cls, Sel_registerName("willClose")) {
+		t.Error("class should not respond to willClose")
+	}
+}
+
+func TestRegisterImplementationMissingMethod(t *testing.T) {
+	proto := Objc_allocateProtocol("UnimplementedProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("reload"), "v@:", true, true)
+	Objc_registerProtocol(proto)
+
+	if _, err := RegisterImplementation("UnimplementedClass", Objc_getClass("NSObject"), &tableDelegate{}, proto); err == nil {
+		t.Error("missing required method should be reported")
+	}
+}
@@ -0,0 +1,39 @@
+// Package dl loads shared libraries for the commands of the module.
+package dl
+
+// #cgo linux LDFLAGS: -ldl
+// #include <dlfcn.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// Libraries is a flag.Value collecting the paths of the libraries to load
+// when the flag is repeated.
+type Libraries []string
+
+func (libs *Libraries) String() string {
+	return strings.Join(*libs, ",")
+}
+
+func (libs *Libraries) Set(path string) error {
+	*libs = append(*libs, path)
+	return nil
+}
+
+// Open loads the shared library path and makes its symbols available to
+// the libraries loaded afterwards. The init functions of the library run
+// before Open returns.
+func Open(path string) error {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	if C.dlopen(cpath, C.RTLD_NOW|C.RTLD_GLOBAL) == nil {
+		return fmt.Errorf("cannot load %s: %s", path, C.GoString(C.dlerror()))
+	}
+
+	return nil
+}
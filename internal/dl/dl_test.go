@@ -0,0 +1,27 @@
+package dl
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestLibraries(t *testing.T) {
+	var libs Libraries
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&libs, "load", "")
+
+	if err := flags.Parse([]string{"-load", "liba.so", "-load", "libb.so"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := libs.String(); s != "liba.so,libb.so" {
+		t.Errorf("libraries should be liba.so,libb.so: %s", s)
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	if err := Open("/nonexistent/libmissing.so"); err == nil {
+		t.Error("loading a missing library should have failed")
+	}
+}
@@ -0,0 +1,108 @@
+package objc
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// Invocation is a method call prepared once for repeated use. The method
+// implementation, its type encoding and the call frame are resolved when
+// the Invocation is created, so invoking it skips the message dispatch.
+type Invocation struct {
+	mutex sync.Mutex
+	sel   Sel
+	imp   Imp
+	sig   *signature
+	frame unsafe.Pointer
+	arena callArena
+}
+
+// NewInvocation prepares the invocation of the instance method sel of cls.
+// It must be invoked on instances of cls that do not override sel. The
+// Invocation must be released with Free.
+func NewInvocation(cls Class, sel Sel) (*Invocation, error) {
+	method := Class_getInstanceMethod(cls, sel)
+
+	if method == nil {
+		return nil, fmt.Errorf("objc: %s does not respond to %s", Class_getName(cls), Sel_getName(sel))
+	}
+
+	sig, err := newSignature(Method_getTypeEncoding(method))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Invocation{
+		sel:   sel,
+		imp:   Method_getImplementation(method),
+		sig:   sig,
+		frame: sig.newFrame(),
+	}, nil
+}
+
+// Invoke calls the method on obj with args and returns the converted
+// result, like Objc_msgSend.
+func (inv *Invocation) Invoke(obj Id, args ...interface{}) (interface{}, error) {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	if err := inv.call(obj, args); err != nil {
+		return nil, err
+	}
+
+	return getValue(inv.sig.retPtr(inv.frame), inv.sig.ret), nil
+}
+
+// InvokeInto calls the method on obj with args and stores the result into
+// the value pointed to by ret, like Objc_msgSend_stret.
+func (inv *Invocation) InvokeInto(ret interface{}, obj Id, args ...interface{}) error {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	if err := inv.call(obj, args); err != nil {
+		return err
+	}
+
+	return storeValue(ret, inv.sig.retPtr(inv.frame), inv.sig.ret, inv.sig.retSize)
+}
+
+// Free releases the memory held by inv.
+func (inv *Invocation) Free() {
+	inv.mutex.Lock()
+	defer inv.mutex.Unlock()
+
+	free(inv.frame)
+	inv.frame = nil
+}
+
+func (inv *Invocation) call(obj Id, args []interface{}) error {
+	sig := inv.sig
+
+	if inv.frame == nil {
+		return fmt.Errorf("objc: invocation of %s is freed", Sel_getName(inv.sel))
+	}
+
+	if obj == nil {
+		return fmt.Errorf("objc: invocation of %s on nil", Sel_getName(inv.sel))
+	}
+
+	if len(args) != len(sig.args)-2 {
+		return fmt.Errorf("objc: %s expects %d arguments: %d given", Sel_getName(inv.sel), len(sig.args)-2, len(args))
+	}
+
+	defer inv.arena.release()
+
+	*(*Id)(sig.argPtr(inv.frame, 0)) = obj
+	*(*Sel)(sig.argPtr(inv.frame, 1)) = inv.sel
+
+	for i, arg := range args {
+		if err := sig.setArg(inv.frame, i+2, arg, &inv.arena); err != nil {
+			return err
+		}
+	}
+
+	sig.ffiCall(unsafe.Pointer(inv.imp), inv.frame)
+	return nil
+}
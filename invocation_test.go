@@ -0,0 +1,126 @@
+package objc
+
+import "testing"
+
+func TestInvocation(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	inv, err := NewInvocation(nsObject, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer inv.Free()
+
+	for i := 0; i < 3; i++ {
+		class, err := inv.Invoke(instance)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if class != nsObject {
+			t.Errorf("class should be %p: %v", nsObject, class)
+		}
+	}
+}
+
+func TestInvocationInto(t *testing.T) {
+	var equal bool
+
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	inv, err := NewInvocation(nsObject, Sel_registerName("isEqual:"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer inv.Free()
+
+	if err = inv.InvokeInto(&equal, instance, instance); err != nil {
+		t.Fatal(err)
+	}
+
+	if !equal {
+		t.Error("instance should be equal to itself")
+	}
+}
+
+func TestInvocationUnknownSelector(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+
+	if _, err := NewInvocation(nsObject, Sel_registerName("unknownSelector")); err == nil {
+		t.Error("preparing unknownSelector should have failed")
+	}
+}
+
+func TestInvocationBadArgumentCount(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	inv, err := NewInvocation(nsObject, Sel_registerName("isEqual:"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer inv.Free()
+
+	if _, err = inv.Invoke(instance); err == nil {
+		t.Error("invoking isEqual: without argument should have failed")
+	}
+}
+
+func TestInvocationFreed(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	inv, err := NewInvocation(nsObject, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv.Free()
+
+	if _, err = inv.Invoke(instance); err == nil {
+		t.Error("invoking a freed invocation should have failed")
+	}
+}
+
+func BenchmarkInvocation(b *testing.B) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	inv, err := NewInvocation(nsObject, Sel_registerName("isEqual:"))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer inv.Free()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		inv.Invoke(instance, instance)
+	}
+}
+
+func BenchmarkMsgSend(b *testing.B) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+	sel := Sel_registerName("isEqual:")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Objc_msgSend(instance, sel, instance)
+	}
+}
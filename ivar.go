@@ -14,6 +14,10 @@ func Ivar_getTypeEncoding(ivar Ivar) string {
 	return C.GoString(C.ivar_getTypeEncoding(ivar))
 }
 
+func Ivar_getOffset(ivar Ivar) int {
+	return int(C.ivar_getOffset(ivar))
+}
+
 func nextIvar(list *C.Ivar) *C.Ivar {
 	ptr := uintptr(unsafe.Pointer(list)) + unsafe.Sizeof(*list)
 	return (*C.Ivar)(unsafe.Pointer(ptr))
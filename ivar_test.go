@@ -28,3 +28,22 @@ func TestIvarGetTypeEncoding(t *testing.T) {
 		t.Errorf("encoding should be %s: %s", typeEncoding, encoding)
 	}
 }
+
+func TestIvarGetOffset(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithIvarForOffsetTest", 0)
+
+	Class_addIvarWithType(class, "a", "c")
+	Class_addIvarWithType(class, "b", "d")
+	Objc_registerClassPair(class)
+
+	a := Ivar_getOffset(Class_getInstanceVariable(class, "a"))
+	b := Ivar_getOffset(Class_getInstanceVariable(class, "b"))
+
+	if a <= 0 {
+		t.Errorf("a offset should be after isa: %d", a)
+	}
+
+	if b <= a || b%8 != 0 {
+		t.Errorf("b offset should be 8 bytes aligned after a: %d", b)
+	}
+}
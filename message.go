@@ -0,0 +1,154 @@
+package objc
+
+// #include <objc/runtime.h>
+// #include <objc/message.h>
+//
+// static void *msgSendFn(void) {
+//     return (void *)objc_msgSend;
+// }
+//
+// static void *msgSendStretFn(void) {
+// #if defined(__arm64__) || defined(__aarch64__)
+//     return (void *)objc_msgSend;
+// #else
+//     return (void *)objc_msgSend_stret;
+// #endif
+// }
+//
+// static void *msgSendFpretFn(void) {
+// #if defined(__i386__) || defined(__x86_64__)
+//     return (void *)objc_msgSend_fpret;
+// #else
+//     return (void *)objc_msgSend;
+// #endif
+// }
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Super mirrors struct objc_super. It designates the receiver of a message
+// and the class where the search for the method implementation starts.
+type Super struct {
+	Receiver   Id
+	SuperClass Class
+}
+
+// NewSuper returns the Super used to reach the superclass implementation of
+// a method implemented by cls. For class methods, cls is the metaclass and
+// receiver the class itself, as set up by NewClassSuper.
+func NewSuper(receiver Id, cls Class) Super {
+	return Super{
+		Receiver:   receiver,
+		SuperClass: Class_getSuperclass(cls),
+	}
+}
+
+// NewClassSuper returns the Super used to reach the superclass
+// implementation of a class method implemented by cls. The metaclass is
+// read from cls itself, so cls does not need to be registered.
+func NewClassSuper(receiver Class, cls Class) Super {
+	return NewSuper(Id(unsafe.Pointer(receiver)), metaClass(cls))
+}
+
+// Objc_msgSend sends sel to obj. args are marshalled according to the type
+// encoding of the method implementing sel and the result is converted back
+// to its Go counterpart. Structs are returned as values of anonymous Go
+// structs with the same layout. Sending a message to nil returns nil.
+//
+// The objc_msgSend, objc_msgSend_stret or objc_msgSend_fpret entry point is
+// chosen from the return type and the calling convention of GOARCH.
+func Objc_msgSend(obj Id, sel Sel, args ...interface{}) (interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	sig, err := lookupSignature(Object_getClass(obj), sel)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.call(msgSendFn(sig), append([]interface{}{obj, sel}, args...))
+}
+
+// Objc_msgSend_stret sends sel to obj like Objc_msgSend and stores the
+// result into the value pointed to by stretAddr. Struct results are copied
+// into Go structs of the same size.
+func Objc_msgSend_stret(stretAddr interface{}, obj Id, sel Sel, args ...interface{}) error {
+	if obj == nil {
+		return nil
+	}
+
+	sig, err := lookupSignature(Object_getClass(obj), sel)
+
+	if err != nil {
+		return err
+	}
+
+	return sig.callInto(stretAddr, msgSendFn(sig), append([]interface{}{obj, sel}, args...))
+}
+
+// Objc_msgSendSuper sends sel to super.Receiver, starting the search for
+// the method implementation at super.SuperClass.
+func Objc_msgSendSuper(super Super, sel Sel, args ...interface{}) (interface{}, error) {
+	if super.Receiver == nil {
+		return nil, nil
+	}
+
+	sig, err := lookupSignature(super.SuperClass, sel)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sig.call(superImp(super, sel, sig), append([]interface{}{super.Receiver, sel}, args...))
+}
+
+// Objc_msgSendSuper_stret sends sel to super.Receiver like
+// Objc_msgSendSuper and stores the result into the value pointed to by
+// stretAddr.
+func Objc_msgSendSuper_stret(stretAddr interface{}, super Super, sel Sel, args ...interface{}) error {
+	if super.Receiver == nil {
+		return nil
+	}
+
+	sig, err := lookupSignature(super.SuperClass, sel)
+
+	if err != nil {
+		return err
+	}
+
+	return sig.callInto(stretAddr, superImp(super, sel, sig), append([]interface{}{super.Receiver, sel}, args...))
+}
+
+func msgSendFn(sig *signature) unsafe.Pointer {
+	switch sig.dispatch {
+	case dispatchStret:
+		return C.msgSendStretFn()
+
+	case dispatchFpret:
+		return C.msgSendFpretFn()
+	}
+
+	return C.msgSendFn()
+}
+
+func superImp(super Super, sel Sel, sig *signature) unsafe.Pointer {
+	if sig.dispatch == dispatchStret {
+		return unsafe.Pointer(Class_getMethodImplementation_stret(super.SuperClass, sel))
+	}
+
+	return unsafe.Pointer(Class_getMethodImplementation(super.SuperClass, sel))
+}
+
+func lookupSignature(cls Class, sel Sel) (*signature, error) {
+	method := Class_getInstanceMethod(cls, sel)
+
+	if method == nil {
+		return nil, fmt.Errorf("objc: %s does not respond to %s", Class_getName(cls), Sel_getName(sel))
+	}
+
+	return newSignature(Method_getTypeEncoding(method))
+}
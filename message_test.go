@@ -0,0 +1,283 @@
+package objc
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+func TestMsgSend(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	ret, err := Objc_msgSend(instance, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if class := ret.(Class); class != nsObject {
+		t.Errorf("class should be %p: %p", nsObject, class)
+	}
+}
+
+func TestMsgSendWithArguments(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+	sel := Sel_registerName("isEqual:")
+
+	ret, err := Objc_msgSend(instance, Sel_registerName("respondsToSelector:"), sel)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != int8(1) && ret != true {
+		t.Errorf("instance should respond to %s: %#v", Sel_getName(sel), ret)
+	}
+}
+
+func TestMsgSendToClass(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+
+	ret, err := Objc_msgSend(Id(unsafe.Pointer(nsObject)), Sel_registerName("new"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if instance := ret.(Id); Object_getClass(instance) != nsObject {
+		t.Errorf("instance class should be %p: %p", nsObject, Object_getClass(instance))
+	}
+}
+
+func TestMsgSendNil(t *testing.T) {
+	ret, err := Objc_msgSend(nil, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != nil {
+		t.Errorf("ret should be nil: %#v", ret)
+	}
+}
+
+func TestMsgSendUnknownSelector(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Objc_msgSend(instance, Sel_registerName("unknownSelector")); err == nil {
+		t.Error("sending unknownSelector should have failed")
+	}
+}
+
+func TestMsgSendBadArgumentCount(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Objc_msgSend(instance, Sel_registerName("isEqual:")); err == nil {
+		t.Error("sending isEqual: without argument should have failed")
+	}
+}
+
+func TestMsgSendBadArgumentType(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Objc_msgSend(instance, Sel_registerName("isEqual:"), 42); err == nil {
+		t.Error("sending isEqual: with an int should have failed")
+	}
+}
+
+func TestMsgSendSuper(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassForMsgSendSuper", 0)
+	Objc_registerClassPair(class)
+	instance := Class_createInstance(class, 0)
+
+	super := NewSuper(instance, class)
+
+	if super.SuperClass != nsObject {
+		t.Errorf("super class should be %p: %p", nsObject, super.SuperClass)
+	}
+
+	ret, err := Objc_msgSendSuper(super, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if retClass := ret.(Class); retClass != class {
+		t.Errorf("class should be %p: %p", class, retClass)
+	}
+}
+
+func TestMsgSendSuperClassMethod(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassForMsgSendSuperClassMethod", 0)
+	Objc_registerClassPair(class)
+
+	super := NewClassSuper(class, class)
+
+	if metaclass := Objc_getMetaClass("NSObject"); super.SuperClass != metaclass {
+		t.Errorf("super class should be %p: %p", metaclass, super.SuperClass)
+	}
+
+	ret, err := Objc_msgSendSuper(super, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if retClass := ret.(Class); retClass != class {
+		t.Errorf("class should be %p: %p", class, retClass)
+	}
+}
+
+func TestNewClassSuperUnregistered(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassForUnregisteredClassSuper", 0)
+	defer Objc_disposeClassPair(class)
+
+	super := NewClassSuper(class, class)
+
+	if metaclass := Objc_getMetaClass("NSObject"); super.SuperClass != metaclass {
+		t.Errorf("super class should be %p: %p", metaclass, super.SuperClass)
+	}
+}
+
+func TestMsgSendSuperNil(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+
+	ret, err := Objc_msgSendSuper(NewSuper(nil, nsObject), Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ret != nil {
+		t.Errorf("ret should be nil: %#v", ret)
+	}
+}
+
+func TestMsgSendSuperUnknownSelector(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	class := Objc_allocateClassPair(nsObject, "ClassForMsgSendSuperUnknown", 0)
+	Objc_registerClassPair(class)
+	instance := Class_createInstance(class, 0)
+
+	if _, err := Objc_msgSendSuper(NewSuper(instance, class), Sel_registerName("unknownSelector")); err == nil {
+		t.Error("sending unknownSelector to super should have failed")
+	}
+}
+
+func TestMsgSendStret(t *testing.T) {
+	var hash uint64
+
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if err := Objc_msgSend_stret(&hash, instance, Sel_registerName("hash")); err != nil {
+		t.Fatal(err)
+	}
+
+	if hash == 0 {
+		t.Error("hash should not be 0")
+	}
+}
+
+type testRect struct {
+	Origin struct{ X, Y float64 }
+	Size   struct{ Width, Height float64 }
+}
+
+func TestMsgSendStructAndFloatReturns(t *testing.T) {
+	frameSel := Sel_registerName("frame")
+	ratioSel := Sel_registerName("ratio")
+	frameTypes := "{CGRect={CGPoint=dd}{CGSize=dd}}@:"
+
+	var frame testRect
+	frame.Origin.X, frame.Origin.Y = 1, 2
+	frame.Size.Width, frame.Size.Height = 3, 4
+
+	base := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassReturningStructs", 0)
+	Class_addMethod(base, frameSel, newTestImp(t, func(self Id, cmd Sel) testRect { return frame }, frameTypes), frameTypes)
+	Class_addMethod(base, ratioSel, newTestImp(t, func(self Id, cmd Sel) float64 { return 1.5 }, "d@:"), "d@:")
+	Objc_registerClassPair(base)
+
+	derived := Objc_allocateClassPair(base, "ClassInheritingStructs", 0)
+	Objc_registerClassPair(derived)
+
+	instance := Class_createInstance(derived, 0)
+	super := NewSuper(instance, derived)
+
+	ret, err := Objc_msgSend(instance, frameSel)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := fmt.Sprint(ret); s != "{{1 2} {3 4}}" {
+		t.Errorf("frame should be {{1 2} {3 4}}: %s", s)
+	}
+
+	var stret testRect
+
+	if err = Objc_msgSend_stret(&stret, instance, frameSel); err != nil {
+		t.Fatal(err)
+	}
+
+	if stret != frame {
+		t.Errorf("frame should be %v: %v", frame, stret)
+	}
+
+	stret = testRect{}
+
+	if err = Objc_msgSendSuper_stret(&stret, super, frameSel); err != nil {
+		t.Fatal(err)
+	}
+
+	if stret != frame {
+		t.Errorf("super frame should be %v: %v", frame, stret)
+	}
+
+	if ret, err = Objc_msgSend(instance, ratioSel); err != nil {
+		t.Fatal(err)
+	}
+
+	if ratio, _ := ret.(float64); ratio != 1.5 {
+		t.Errorf("ratio should be 1.5: %v", ret)
+	}
+
+	var ratio float64
+
+	if err = Objc_msgSend_stret(&ratio, instance, ratioSel); err != nil {
+		t.Fatal(err)
+	}
+
+	if ratio != 1.5 {
+		t.Errorf("ratio should be 1.5: %v", ratio)
+	}
+
+	ratio = 0
+
+	if err = Objc_msgSendSuper_stret(&ratio, super, ratioSel); err != nil {
+		t.Fatal(err)
+	}
+
+	if ratio != 1.5 {
+		t.Errorf("super ratio should be 1.5: %v", ratio)
+	}
+}
+
+func TestMsgSendStretBadDestination(t *testing.T) {
+	var name string
+
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if err := Objc_msgSend_stret(&name, instance, Sel_registerName("hash")); err == nil {
+		t.Error("storing hash into a string should have failed")
+	}
+}
@@ -3,7 +3,11 @@ package objc
 // #include <stdlib.h>
 // #include <objc/runtime.h>
 import "C"
-import "unsafe"
+import (
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
 
 type Method C.Method
 
@@ -19,6 +23,23 @@ func makeMethodDescription(description C.struct_objc_method_description) MethodD
 	}
 }
 
+// Signature parses the type encoding of the described method.
+func (description MethodDescription) Signature() (*encoding.MethodSignature, error) {
+	return encoding.ParseMethodSignature(description.Types)
+}
+
+// Declaration returns the Objective-C declaration of the described method,
+// such as "- (void)setFoo:(int)foo;".
+func (description MethodDescription) Declaration(isClassMethod bool) (string, error) {
+	sig, err := description.Signature()
+
+	if err != nil {
+		return "", err
+	}
+
+	return sig.Decl(Sel_getName(description.Name), isClassMethod), nil
+}
+
 func Method_getName(method Method) Sel {
 	return Sel(C.method_getName(method))
 }
@@ -32,11 +53,28 @@ func Method_getTypeEncoding(method Method) string {
 }
 
 func Method_copyReturnType(method Method) string {
-	return C.GoString(C.method_copyReturnType(method))
+	creturnType := C.method_copyReturnType(method)
+	defer free(unsafe.Pointer(creturnType))
+
+	return C.GoString(creturnType)
 }
 
 func Method_copyArgumentType(method Method, index uint) string {
-	return C.GoString(C.method_copyArgumentType(method, C.uint(index)))
+	cargType := C.method_copyArgumentType(method, C.uint(index))
+	defer free(unsafe.Pointer(cargType))
+
+	return C.GoString(cargType)
+}
+
+// Method_getSignature parses the type encoding of method.
+func Method_getSignature(method Method) (*encoding.MethodSignature, error) {
+	return encoding.ParseMethodSignature(Method_getTypeEncoding(method))
+}
+
+// MethodDeclaration returns the Objective-C declaration of method, such as
+// "- (void)setFoo:(int)foo;".
+func MethodDeclaration(method Method, isClassMethod bool) (string, error) {
+	return Method_getDescription(method).Declaration(isClassMethod)
 }
 
 func Method_getNumberOfArguments(method Method) uint {
@@ -1,11 +1,6 @@
 package objc
 
-import (
-	"testing"
-	"unsafe"
-
-	"github.com/achille-roussel/go-ffi"
-)
+import "testing"
 
 func TestMethodGetName(t *testing.T) {
 	nsObject := Objc_getClass("NSObject")
@@ -33,10 +28,10 @@ func TestMethodGetTypeEncoding(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), methodTypes)
+	Class_addMethod(class, sel, imp, methodTypes)
 
 	method := Class_getInstanceMethod(class, sel)
 
@@ -51,10 +46,10 @@ func TestMethodCopyReturnType(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), methodTypes)
+	Class_addMethod(class, sel, imp, methodTypes)
 
 	method := Class_getInstanceMethod(class, sel)
 	expectedReturnType := "v"
@@ -70,10 +65,10 @@ func TestMethodCopyArgumentType(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), methodTypes)
+	Class_addMethod(class, sel, imp, methodTypes)
 
 	method := Class_getInstanceMethod(class, sel)
 	expectedArgType := "@"
@@ -89,10 +84,10 @@ func TestMethodGetNumberOfArguments(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), methodTypes)
+	Class_addMethod(class, sel, imp, methodTypes)
 
 	method := Class_getInstanceMethod(class, sel)
 	expectedArgCount := uint(2)
@@ -108,10 +103,10 @@ func TestMethodGetDescription(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closure := ffi.Closure(func(id Id, sel Sel) {})
+	imp := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
-	Class_addMethod(class, sel, Imp(unsafe.Pointer(closure.Pointer())), methodTypes)
+	Class_addMethod(class, sel, imp, methodTypes)
 
 	method := Class_getInstanceMethod(class, sel)
 	description := Method_getDescription(method)
@@ -125,16 +120,61 @@ func TestMethodGetDescription(t *testing.T) {
 	}
 }
 
+func TestMethodGetSignature(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	method := Class_getInstanceMethod(nsObject, Sel_registerName("isEqual:"))
+	sig, err := Method_getSignature(method)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if types := sig.String(); types != "c@:@" && types != "B@:@" {
+		t.Errorf("isEqual: signature should be c@:@ or B@:@: %s", types)
+	}
+
+	if l := len(sig.Args); l != 3 {
+		t.Errorf("isEqual: should have 3 arguments: %d", l)
+	}
+}
+
+func TestMethodDescriptionSignature(t *testing.T) {
+	description := MethodDescription{Types: "v24@0:8i16"}
+	sig, err := description.Signature()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if types := sig.String(); types != "v@:i" {
+		t.Errorf("description signature should be v@:i: %s", types)
+	}
+}
+
+func TestMethodDeclaration(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithMethodForDeclarationTest", 0)
+	sel := Sel_registerName("setFoo:")
+	Class_addMethod(class, sel, newTestImp(t, func(id Id, sel Sel, n int32) {}, "v@:i"), "v@:i")
+
+	decl, err := MethodDeclaration(Class_getInstanceMethod(class, sel), false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "- (void)setFoo:(int)foo;"; decl != expected {
+		t.Errorf("declaration should be %s: %s", expected, decl)
+	}
+}
+
 func TestMethodSetImplementation(t *testing.T) {
 	nsObject := Objc_getClass("NSObject")
 	className := "ClassForExchangeMethodImp"
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodName := "MethodA"
-	closureA := ffi.Closure(func(id Id, sel Sel) {})
-	impA := Imp(unsafe.Pointer(closureA.Pointer()))
-	closureB := ffi.Closure(func(id Id, sel Sel) {})
-	impB := Imp(unsafe.Pointer(closureB.Pointer()))
+	impA := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
+	impB := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	sel := Sel_registerName(methodName)
 	methodTypes := "v@:"
 	Class_addMethod(class, sel, impA, methodTypes)
@@ -152,16 +192,14 @@ func TestMethodExchangeImplementations(t *testing.T) {
 	class := Objc_allocateClassPair(nsObject, className, 0)
 
 	methodAName := "MethodA"
-	closureA := ffi.Closure(func(id Id, sel Sel) {})
-	impA := Imp(unsafe.Pointer(closureA.Pointer()))
+	impA := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	selA := Sel_registerName(methodAName)
 	methodTypes := "v@:"
 	Class_addMethod(class, selA, impA, methodTypes)
 	methodA := Class_getInstanceMethod(class, selA)
 
 	methodBName := "MethodB"
-	closureB := ffi.Closure(func(id Id, sel Sel) {})
-	impB := Imp(unsafe.Pointer(closureB.Pointer()))
+	impB := newTestImp(t, func(id Id, sel Sel) {}, "v@:")
 	selB := Sel_registerName(methodBName)
 	Class_addMethod(class, selB, impB, methodTypes)
 	methodB := Class_getInstanceMethod(class, selB)
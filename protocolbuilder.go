@@ -0,0 +1,227 @@
+package objc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// ProtocolBuilder collects the definition of a protocol and registers it
+// at once:
+//
+//	proto, err := NewProtocolBuilder("Counting").
+//		Method(Sel_registerName("count"), "q@:").
+//		OptionalMethod(Sel_registerName("reset"), "v@:").
+//		Property("title", PropertyAttributes{Type: "@", Copy: true}).
+//		Adopt(Objc_getProtocol("NSObject")).
+//		Register()
+//
+// Every definition is validated before the protocol is allocated, since a
+// registered protocol cannot be modified or disposed.
+type ProtocolBuilder struct {
+	name       string
+	methods    []protocolMethodDef
+	properties []protocolPropertyDef
+	parents    []Protocol
+}
+
+type protocolMethodDef struct {
+	name       Sel
+	types      string
+	required   bool
+	isInstance bool
+}
+
+type protocolPropertyDef struct {
+	name     string
+	attrs    PropertyAttributes
+	required bool
+}
+
+// NewProtocolBuilder returns a builder for the protocol name.
+func NewProtocolBuilder(name string) *ProtocolBuilder {
+	return &ProtocolBuilder{name: name}
+}
+
+// Method declares a required instance method with the type encoding types.
+func (b *ProtocolBuilder) Method(name Sel, types string) *ProtocolBuilder {
+	return b.method(name, types, true, true)
+}
+
+// OptionalMethod declares an optional instance method.
+func (b *ProtocolBuilder) OptionalMethod(name Sel, types string) *ProtocolBuilder {
+	return b.method(name, types, false, true)
+}
+
+// ClassMethod declares a required class method.
+func (b *ProtocolBuilder) ClassMethod(name Sel, types string) *ProtocolBuilder {
+	return b.method(name, types, true, false)
+}
+
+// OptionalClassMethod declares an optional class method.
+func (b *ProtocolBuilder) OptionalClassMethod(name Sel, types string) *ProtocolBuilder {
+	return b.method(name, types, false, false)
+}
+
+// Property declares a required instance property.
+func (b *ProtocolBuilder) Property(name string, attrs PropertyAttributes) *ProtocolBuilder {
+	b.properties = append(b.properties, protocolPropertyDef{name: name, attrs: attrs, required: true})
+	return b
+}
+
+// OptionalProperty declares an optional instance property.
+func (b *ProtocolBuilder) OptionalProperty(name string, attrs PropertyAttributes) *ProtocolBuilder {
+	b.properties = append(b.properties, protocolPropertyDef{name: name, attrs: attrs})
+	return b
+}
+
+// Adopt declares that the protocol adopts proto, which must be registered
+// since the runtime ignores protocols under construction.
+func (b *ProtocolBuilder) Adopt(proto Protocol) *ProtocolBuilder {
+	b.parents = append(b.parents, proto)
+	return b
+}
+
+// Register validates the definitions, then allocates and registers the
+// protocol. When a definition is invalid, nothing is allocated and the
+// returned *BuildError lists every invalid definition.
+func (b *ProtocolBuilder) Register() (Protocol, error) {
+	if b.name == "" {
+		return nil, &BuildError{Errors: []error{fmt.Errorf("missing protocol name")}}
+	}
+
+	if errs := b.validate(); len(errs) != 0 {
+		return nil, &BuildError{Name: b.name, Errors: errs}
+	}
+
+	proto := Objc_allocateProtocol(b.name)
+
+	if proto == nil {
+		return nil, &BuildError{Name: b.name, Errors: []error{fmt.Errorf("cannot allocate protocol")}}
+	}
+
+	for _, method := range b.methods {
+		Protocol_addMethodDescription(proto, method.name, method.types, method.required, method.isInstance)
+	}
+
+	for _, property := range b.properties {
+		Protocol_addProperty(proto, property.name, property.attrs.List(), property.required, true)
+	}
+
+	for _, parent := range b.parents {
+		Protocol_addProtocol(proto, parent)
+	}
+
+	Objc_registerProtocol(proto)
+	return proto, nil
+}
+
+func (b *ProtocolBuilder) method(name Sel, types string, required bool, isInstance bool) *ProtocolBuilder {
+	b.methods = append(b.methods, protocolMethodDef{
+		name:       name,
+		types:      types,
+		required:   required,
+		isInstance: isInstance,
+	})
+
+	return b
+}
+
+func (b *ProtocolBuilder) validate() (errs []error) {
+	if Objc_getProtocol(b.name) != nil {
+		errs = append(errs, fmt.Errorf("protocol already exists"))
+	}
+
+	methods := map[string]bool{}
+
+	for _, method := range b.methods {
+		if method.name == nil {
+			errs = append(errs, fmt.Errorf("cannot declare a method without name"))
+			continue
+		}
+
+		selector := Sel_getName(method.name)
+		key := "+" + selector
+
+		if method.isInstance {
+			key = "-" + selector
+		}
+
+		if methods[key] {
+			errs = append(errs, fmt.Errorf("duplicate method %s", key))
+			continue
+		}
+
+		methods[key] = true
+
+		if err := checkSelectorTypes(selector, method.types); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	properties := map[string]bool{}
+
+	for _, property := range b.properties {
+		if property.name == "" {
+			errs = append(errs, fmt.Errorf("cannot declare a property without name"))
+			continue
+		}
+
+		if properties[property.name] {
+			errs = append(errs, fmt.Errorf("duplicate property %s", property.name))
+			continue
+		}
+
+		properties[property.name] = true
+
+		if _, err := encoding.Parse(property.attrs.Type); err != nil {
+			errs = append(errs, fmt.Errorf("property %s: %v", property.name, err))
+		}
+	}
+
+	parents := map[Protocol]bool{}
+
+	for _, parent := range b.parents {
+		if parent == nil {
+			errs = append(errs, fmt.Errorf("cannot adopt a nil protocol"))
+			continue
+		}
+
+		name := Protocol_getName(parent)
+
+		if parents[parent] {
+			errs = append(errs, fmt.Errorf("duplicate adopted protocol %s", name))
+			continue
+		}
+
+		if Objc_getProtocol(name) != parent {
+			errs = append(errs, fmt.Errorf("cannot adopt %s: not registered", name))
+			continue
+		}
+
+		parents[parent] = true
+	}
+
+	return
+}
+
+// checkSelectorTypes checks that types is a method signature taking an
+// argument for each label of selector. Empty types are accepted.
+func checkSelectorTypes(selector string, types string) error {
+	if types == "" {
+		return nil
+	}
+
+	sig, err := encoding.ParseMethodSignature(types)
+
+	if err != nil {
+		return fmt.Errorf("method %s: %v", selector, err)
+	}
+
+	if argc := strings.Count(selector, ":") + 2; len(sig.Args) != argc {
+		return fmt.Errorf("method %s takes %d arguments: %s", selector, argc, types)
+	}
+
+	return nil
+}
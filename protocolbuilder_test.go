@@ -0,0 +1,116 @@
+package objc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtocolBuilder(t *testing.T) {
+	parent, err := NewProtocolBuilder("ProtocolBuiltParent").
+		Method(Sel_registerName("reset"), "v@:").
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proto, err := NewProtocolBuilder("ProtocolBuiltWithBuilder").
+		Method(Sel_registerName("count"), "q@:").
+		OptionalMethod(Sel_registerName("insert:atIndex:"), "v@:@q").
+		ClassMethod(Sel_registerName("sharedInstance"), "@@:").
+		OptionalClassMethod(Sel_registerName("version"), "i@:").
+		Property("title", PropertyAttributes{Type: "@", Copy: true}).
+		Adopt(parent).
+		Register()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if Objc_getProtocol("ProtocolBuiltWithBuilder") != proto {
+		t.Error("protocol should be registered")
+	}
+
+	tests := []struct {
+		selector   string
+		required   bool
+		isInstance bool
+		types      string
+	}{
+		{"count", true, true, "q@:"},
+		{"insert:atIndex:", false, true, "v@:@q"},
+		{"sharedInstance", true, false, "@@:"},
+		{"version", false, false, "i@:"},
+	}
+
+	for _, test := range tests {
+		description := Protocol_getMethodDescription(proto, Sel_registerName(test.selector), test.required, test.isInstance)
+
+		if description.Types != test.types {
+			t.Errorf("%s types should be %s: %s", test.selector, test.types, description.Types)
+		}
+	}
+
+	if Protocol_getProperty(proto, "title", true, true) == nil {
+		t.Error("protocol should have the title property")
+	}
+
+	if !Protocol_conformsToProtocol(proto, parent) {
+		t.Error("protocol should adopt ProtocolBuiltParent")
+	}
+}
+
+func TestProtocolBuilderErrors(t *testing.T) {
+	count := Sel_registerName("count")
+
+	_, err := NewProtocolBuilder("ProtocolBuiltWithErrors").
+		Method(count, "q@:").
+		OptionalMethod(count, "q@:").
+		ClassMethod(count, "q@:").
+		Method(Sel_registerName("insert:"), "v@:").
+		Method(Sel_registerName("broken"), "{").
+		Property("title", PropertyAttributes{Type: "@"}).
+		OptionalProperty("title", PropertyAttributes{Type: "@"}).
+		Adopt(nil).
+		Register()
+
+	var buildErr *BuildError
+
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("error should be a *BuildError: %v", err)
+	}
+
+	if l := len(buildErr.Errors); l != 5 {
+		t.Errorf("build error should list 5 errors: %v", buildErr)
+	}
+
+	if Objc_getProtocol("ProtocolBuiltWithErrors") != nil {
+		t.Error("invalid protocol should not be registered")
+	}
+}
+
+func TestProtocolBuilderUnregisteredParent(t *testing.T) {
+	parent := Objc_allocateProtocol("ProtocolUnderConstruction")
+
+	_, err := NewProtocolBuilder("ProtocolAdoptingUnregistered").
+		Adopt(parent).
+		Register()
+
+	if err == nil {
+		t.Error("adopting an unregistered protocol should have failed")
+	}
+
+	if Objc_getProtocol("ProtocolAdoptingUnregistered") != nil {
+		t.Error("invalid protocol should not be registered")
+	}
+}
+
+func TestProtocolBuilderExistingProtocol(t *testing.T) {
+	if _, err := NewProtocolBuilder("ProtocolBuiltTwice").Register(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewProtocolBuilder("ProtocolBuiltTwice").Register(); err == nil {
+		t.Error("existing protocol should be rejected")
+	}
+}
@@ -0,0 +1,493 @@
+package objc
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// ProtocolMethod is an instance method declared by a protocol, either
+// directly or as the accessor of one of its properties.
+type ProtocolMethod struct {
+	Protocol string
+	Selector string
+	Types    string
+	Required bool
+
+	// Property is the name of the property the method is an accessor of. It
+	// is empty for methods declared directly.
+	Property string
+}
+
+// ProtocolMethods returns the instance methods declared by proto and by
+// the protocols it adopts, breadth first, sorted by selector within each
+// protocol. The accessors of required properties that are not declared as
+// methods are included. Protocols named in exclude are skipped along with
+// the protocols they adopt.
+func ProtocolMethods(proto Protocol, exclude ...string) (methods []ProtocolMethod) {
+	visited := map[string]bool{}
+	selectors := map[string]bool{}
+
+	for _, name := range exclude {
+		visited[name] = true
+	}
+
+	protocols := []Protocol{proto}
+
+	for len(protocols) != 0 {
+		p := protocols[0]
+		protocols = protocols[1:]
+		protoName := Protocol_getName(p)
+
+		if visited[protoName] {
+			continue
+		}
+
+		visited[protoName] = true
+		protocols = append(protocols, Protocol_copyProtocolList(p)...)
+
+		var declared []ProtocolMethod
+
+		for _, required := range []bool{true, false} {
+			for _, description := range Protocol_copyMethodDescriptionList(p, required, true) {
+				declared = append(declared, ProtocolMethod{
+					Protocol: protoName,
+					Selector: Sel_getName(description.Name),
+					Types:    description.Types,
+					Required: required,
+				})
+			}
+		}
+
+		for _, property := range Protocol_copyPropertyList(p) {
+			declared = append(declared, propertyAccessors(protoName, property)...)
+		}
+
+		sort.SliceStable(declared, func(i, j int) bool {
+			return declared[i].Selector < declared[j].Selector
+		})
+
+		for _, method := range declared {
+			if selectors[method.Selector] {
+				continue
+			}
+
+			selectors[method.Selector] = true
+			methods = append(methods, method)
+		}
+	}
+
+	return
+}
+
+// propertyAccessors returns the getter and, unless the property is read
+// only, the setter of a required protocol property.
+func propertyAccessors(protoName string, property Property) []ProtocolMethod {
+	name := Property_getName(property)
+	attrs, err := ParsePropertyAttributes(Property_getAttributes(property))
+
+	if err != nil || attrs.Type == "" {
+		return nil
+	}
+
+	getter := ProtocolMethod{
+		Protocol: protoName,
+		Selector: name,
+		Types:    attrs.Type + "@:",
+		Required: true,
+		Property: name,
+	}
+
+	if attrs.Getter != "" {
+		getter.Selector = attrs.Getter
+	}
+
+	if attrs.ReadOnly {
+		return []ProtocolMethod{getter}
+	}
+
+	setter := ProtocolMethod{
+		Protocol: protoName,
+		Selector: "set" + strings.ToUpper(name[:1]) + name[1:] + ":",
+		Types:    "v@:" + attrs.Type,
+		Required: true,
+		Property: name,
+	}
+
+	if attrs.Setter != "" {
+		setter.Selector = attrs.Setter
+	}
+
+	return []ProtocolMethod{getter, setter}
+}
+
+// ProtocolMethodNames returns the names a Go method implementing the
+// method selector may have. Colons become underscores and the first letter
+// of each label is capitalized, so tableView:didSelectRow: becomes
+// TableView_DidSelectRow_.
+// A selector with a single trailing colon may also drop it: setTitle:
+// becomes SetTitle or SetTitle_. Selectors that contain underscores have
+// no Go name.
+func ProtocolMethodNames(selector string) []string {
+	if selector == "" || strings.Contains(selector, "_") {
+		return nil
+	}
+
+	labels := strings.Split(selector, ":")
+
+	for i, label := range labels {
+		if label != "" {
+			labels[i] = strings.ToUpper(label[:1]) + label[1:]
+		}
+	}
+
+	name := strings.Join(labels, "_")
+
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return nil
+	}
+
+	if strings.Count(selector, ":") == 1 && strings.HasSuffix(selector, ":") {
+		return []string{strings.TrimSuffix(name, "_"), name}
+	}
+
+	return []string{name}
+}
+
+// ProtocolInterfaceOptions configures GenerateProtocolInterface.
+type ProtocolInterfaceOptions struct {
+	// Package is the name of the generated package.
+	Package string
+
+	// Interface is the name of the generated interface. It defaults to the
+	// name of the protocol.
+	Interface string
+
+	// Exclude lists protocols whose methods are not generated, such as
+	// NSObject whose methods are inherited from the superclass.
+	Exclude []string
+}
+
+// GenerateProtocolInterface returns the gofmt-ed source of a Go file
+// declaring an interface with a method for each required instance method
+// of proto, an interface with the optional ones, the structs used by their
+// signatures and a function registering an implementation of the interface
+// as a class conforming to proto with RegisterImplementation.
+//
+// Methods receive self and _cmd first and are named as described in
+// ProtocolMethodNames. The char type is assumed to be a BOOL and is mapped
+// to bool. Methods whose types have no Go counterpart are listed as
+// comments. Class methods are not generated.
+func GenerateProtocolInterface(proto Protocol, options ProtocolInterfaceOptions) ([]byte, error) {
+	if proto == nil {
+		return nil, fmt.Errorf("objc: cannot generate an interface for a nil protocol")
+	}
+
+	if options.Package == "" {
+		return nil, fmt.Errorf("objc: missing package name")
+	}
+
+	protoName := Protocol_getName(proto)
+	g := &protocolGenerator{structs: map[string]string{}}
+
+	if g.iface = options.Interface; g.iface == "" {
+		g.iface = protoName
+	}
+
+	if !token.IsIdentifier(g.iface) || !token.IsExported(g.iface) {
+		return nil, fmt.Errorf("objc: %q is not an exported Go identifier", g.iface)
+	}
+
+	var required, optional bytes.Buffer
+	names := map[string]bool{}
+
+	for _, method := range ProtocolMethods(proto, options.Exclude...) {
+		if method.Required {
+			g.method(&required, method, names)
+		} else {
+			g.method(&optional, method, names)
+		}
+	}
+
+	var src bytes.Buffer
+
+	fmt.Fprintf(&src, "// Code generated by objc-protogen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", options.Package)
+	fmt.Fprintf(&src, "import (\n")
+
+	if g.unsafe {
+		fmt.Fprintf(&src, "\t\"unsafe\"\n\n")
+	}
+
+	fmt.Fprintf(&src, "\tobjc %q\n)\n\n", reflect.TypeOf(Id(nil)).PkgPath())
+
+	structNames := make([]string, 0, len(g.structs))
+
+	for name := range g.structs {
+		structNames = append(structNames, name)
+	}
+
+	sort.Strings(structNames)
+
+	for _, name := range structNames {
+		fmt.Fprintf(&src, "type %s %s\n\n", name, g.structs[name])
+	}
+
+	fmt.Fprintf(&src, "// %s mirrors the required instance methods of the Objective-C\n", g.iface)
+	fmt.Fprintf(&src, "// protocol %s.\n", protoName)
+	fmt.Fprintf(&src, "type %s interface {\n%s}\n\n", g.iface, required.String())
+
+	if optional.Len() != 0 {
+		fmt.Fprintf(&src, "// %sOptional mirrors the optional instance methods of the\n", g.iface)
+		fmt.Fprintf(&src, "// Objective-C protocol %s. Implementations of %s may\n", protoName, g.iface)
+		fmt.Fprintf(&src, "// implement any of them.\n")
+		fmt.Fprintf(&src, "type %sOptional interface {\n%s}\n\n", g.iface, optional.String())
+	}
+
+	fmt.Fprintf(&src, "// Register%s registers the class name, a subclass of superclass\n", g.iface)
+	fmt.Fprintf(&src, "// conforming to %s, whose methods are implemented by impl.\n", protoName)
+	fmt.Fprintf(&src, "func Register%s(name string, superclass objc.Class, impl %s) (objc.Class, error) {\n", g.iface, g.iface)
+	fmt.Fprintf(&src, "\tproto := objc.Objc_getProtocol(%q)\n", protoName)
+	fmt.Fprintf(&src, "\treturn objc.RegisterImplementation(name, superclass, impl, proto)\n}\n")
+
+	out, err := format.Source(src.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("objc: cannot format the interface of %s: %v", protoName, err)
+	}
+
+	return out, nil
+}
+
+type protocolGenerator struct {
+	iface   string
+	structs map[string]string
+	unsafe  bool
+
+	// The structs and unsafe use of the method being converted, kept until
+	// the method is known to be supported.
+	pendingStructs map[string]string
+	pendingUnsafe  bool
+}
+
+// method writes the Go method mirroring method to w, or a comment when it
+// cannot be mirrored.
+func (g *protocolGenerator) method(w *bytes.Buffer, method ProtocolMethod, names map[string]bool) {
+	g.pendingStructs = map[string]string{}
+	g.pendingUnsafe = false
+
+	sig, err := encoding.ParseMethodSignature(method.Types)
+
+	if err != nil {
+		fmt.Fprintf(w, "\t// %s is not supported: %v\n", method.Selector, err)
+		return
+	}
+
+	decl := sig.Decl(method.Selector, false)
+	candidates := ProtocolMethodNames(method.Selector)
+	name := ""
+
+	for _, candidate := range candidates {
+		if !names[candidate] {
+			name = candidate
+			break
+		}
+	}
+
+	if name == "" {
+		fmt.Fprintf(w, "\t// %s is not supported: no Go method name\n", decl)
+		return
+	}
+
+	if len(sig.Args) < 2 {
+		fmt.Fprintf(w, "\t// %s is not supported: missing self and _cmd\n", decl)
+		return
+	}
+
+	params := []string{"self objc.Id", "cmd objc.Sel"}
+
+	for i, paramName := range sig.ParamNames(method.Selector) {
+		typ, err := g.goType(sig.Args[i+2].Type, true)
+
+		if err != nil {
+			fmt.Fprintf(w, "\t// %s is not supported: %v\n", decl, err)
+			return
+		}
+
+		if token.IsKeyword(paramName) || paramName == "self" || paramName == "cmd" {
+			paramName += "Arg"
+		}
+
+		params = append(params, paramName+" "+typ)
+	}
+
+	ret := ""
+
+	if sig.Return.Kind != encoding.Void {
+		if sig.Return.Kind == encoding.CString {
+			fmt.Fprintf(w, "\t// %s is not supported: C string return value\n", decl)
+			return
+		}
+
+		if ret, err = g.goType(sig.Return, true); err != nil {
+			fmt.Fprintf(w, "\t// %s is not supported: %v\n", decl, err)
+			return
+		}
+
+		ret = " " + ret
+	}
+
+	for structName, def := range g.pendingStructs {
+		g.structs[structName] = def
+	}
+
+	g.unsafe = g.unsafe || g.pendingUnsafe
+	names[name] = true
+	fmt.Fprintf(w, "\t// %s\n", decl)
+	fmt.Fprintf(w, "\t%s(%s)%s\n", name, strings.Join(params, ", "), ret)
+}
+
+// goType returns the Go type mirroring t. Top level types are arguments
+// or return values, where a char is a BOOL and a C string is a Go string.
+func (g *protocolGenerator) goType(t *encoding.Type, top bool) (string, error) {
+	switch t.Kind {
+	case encoding.Char:
+		if top {
+			return "bool", nil
+		}
+
+		return "int8", nil
+
+	case encoding.UChar:
+		return "uint8", nil
+
+	case encoding.Short:
+		return "int16", nil
+
+	case encoding.UShort:
+		return "uint16", nil
+
+	case encoding.Int, encoding.Long:
+		return "int32", nil
+
+	case encoding.UInt, encoding.ULong:
+		return "uint32", nil
+
+	case encoding.LongLong:
+		return "int64", nil
+
+	case encoding.ULongLong:
+		return "uint64", nil
+
+	case encoding.Float:
+		return "float32", nil
+
+	case encoding.Double:
+		return "float64", nil
+
+	case encoding.Bool:
+		return "bool", nil
+
+	case encoding.Object, encoding.Block:
+		return "objc.Id", nil
+
+	case encoding.Class:
+		return "objc.Class", nil
+
+	case encoding.Selector:
+		return "objc.Sel", nil
+
+	case encoding.CString:
+		if top {
+			return "string", nil
+		}
+
+		g.pendingUnsafe = true
+		return "unsafe.Pointer", nil
+
+	case encoding.Pointer, encoding.Atom, encoding.Unknown:
+		g.pendingUnsafe = true
+		return "unsafe.Pointer", nil
+
+	case encoding.Array:
+		elem, err := g.goType(t.Elem, false)
+
+		if err != nil {
+			return "", err
+		}
+
+		return "[" + strconv.Itoa(t.Len) + "]" + elem, nil
+
+	case encoding.Struct:
+		return g.structType(t)
+	}
+
+	return "", fmt.Errorf("%s has no Go counterpart", t.TypeName())
+}
+
+// structType returns the Go type mirroring the struct t. Named structs
+// are declared once as Go types, anonymous ones are inlined.
+func (g *protocolGenerator) structType(t *encoding.Type) (string, error) {
+	name := strings.TrimLeft(t.Name, "_")
+
+	if name == "?" {
+		name = ""
+	}
+
+	if name != "" {
+		name = string(unicode.ToUpper(rune(name[0]))) + name[1:]
+
+		if !token.IsIdentifier(name) || name == g.iface {
+			return "", fmt.Errorf("struct %s has no Go name", t.Name)
+		}
+
+		if _, ok := g.structs[name]; ok {
+			return name, nil
+		}
+
+		if _, ok := g.pendingStructs[name]; ok {
+			return name, nil
+		}
+	}
+
+	if len(t.Fields) == 0 {
+		return "", fmt.Errorf("%s has no fields", t.TypeName())
+	}
+
+	var def strings.Builder
+
+	def.WriteString("struct {\n")
+
+	for i, field := range t.Fields {
+		typ, err := g.goType(field.Type, false)
+
+		if err != nil {
+			return "", err
+		}
+
+		fieldName := "F" + strconv.Itoa(i)
+
+		if field.Name != "" {
+			fieldName = strings.ToUpper(field.Name[:1]) + field.Name[1:]
+		}
+
+		def.WriteString("\t" + fieldName + " " + typ + "\n")
+	}
+
+	def.WriteString("}")
+
+	if name == "" {
+		return def.String(), nil
+	}
+
+	g.pendingStructs[name] = def.String()
+	return name, nil
+}
@@ -0,0 +1,194 @@
+package objc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProtocolMethods(t *testing.T) {
+	parent := Objc_allocateProtocol("ListedParentProto")
+	Protocol_addMethodDescription(parent, Sel_registerName("reset"), "v@:", true, true)
+	Objc_registerProtocol(parent)
+
+	proto := Objc_allocateProtocol("ListedProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("tableView:didSelectRow:"), "v@:@q", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("frameForView:"), "{CGRect={CGPoint=dd}{CGSize=dd}}@:@", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("shouldClose"), "c@:", false, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("sharedInstance"), "@@:", true, false)
+	Protocol_addProperty(proto, "title", []PropertyAttribute{{Name: "T", Value: "@"}, {Name: "C"}}, true, true)
+	Protocol_addProtocol(proto, parent)
+	Objc_registerProtocol(proto)
+
+	var selectors []string
+
+	for _, method := range ProtocolMethods(proto) {
+		selectors = append(selectors, method.Selector)
+	}
+
+	expected := []string{"frameForView:", "setTitle:", "shouldClose", "tableView:didSelectRow:", "title", "reset"}
+
+	if !reflect.DeepEqual(selectors, expected) {
+		t.Errorf("selectors should be %v: %v", expected, selectors)
+	}
+
+	if methods := ProtocolMethods(proto, "ListedParentProto"); len(methods) != len(expected)-1 {
+		t.Errorf("excluded protocol methods should be skipped: %v", methods)
+	}
+}
+
+func TestProtocolMethodNames(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{"reset", []string{"Reset"}},
+		{"setTitle:", []string{"SetTitle", "SetTitle_"}},
+		{"tableView:didSelectRow:", []string{"TableView_DidSelectRow_"}},
+		{"_private", nil},
+		{"foo_bar:", nil},
+	}
+
+	for _, test := range tests {
+		if names := ProtocolMethodNames(test.selector); !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s names should be %v: %v", test.selector, test.expected, names)
+		}
+	}
+}
+
+func TestGenerateProtocolInterface(t *testing.T) {
+	parent := Objc_allocateProtocol("GeneratedParentProto")
+	Protocol_addMethodDescription(parent, Sel_registerName("reset"), "v@:", true, true)
+	Objc_registerProtocol(parent)
+
+	proto := Objc_allocateProtocol("GeneratedProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("tableView:didSelectRow:"), "v@:@q", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("frameForView:"), "{CGRect={CGPoint=dd}{CGSize=dd}}@:@", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("shouldClose"), "c@:", false, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("sharedInstance"), "@@:", true, false)
+	Protocol_addProperty(proto, "title", []PropertyAttribute{{Name: "T", Value: "@"}, {Name: "C"}}, true, true)
+	Protocol_addProtocol(proto, parent)
+	Objc_registerProtocol(proto)
+
+	src, err := GenerateProtocolInterface(proto, ProtocolInterfaceOptions{
+		Package:   "delegate",
+		Interface: "TableDelegate",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"// Code generated by objc-protogen. DO NOT EDIT.",
+		"package delegate",
+		"type CGRect struct {",
+		"type TableDelegate interface {",
+		"TableView_DidSelectRow_(self objc.Id, cmd objc.Sel, view objc.Id, row int64)",
+		"FrameForView(self objc.Id, cmd objc.Sel, view objc.Id) CGRect",
+		"SetTitle(self objc.Id, cmd objc.Sel, title objc.Id)",
+		"Reset(self objc.Id, cmd objc.Sel)",
+		"type TableDelegateOptional interface {",
+		"ShouldClose(self objc.Id, cmd objc.Sel) bool",
+		"func RegisterTableDelegate(name string, superclass objc.Class, impl TableDelegate) (objc.Class, error) {",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("generated source should contain %q:\n%s", expected, src)
+		}
+	}
+
+	if strings.Contains(string(src), "SharedInstance") {
+		t.Errorf("class methods should not be generated:\n%s", src)
+	}
+
+	if err = checkGeneratedSource(src); err != nil {
+		t.Errorf("generated source should type-check: %v\n%s", err, src)
+	}
+
+	if _, err = GenerateProtocolInterface(proto, ProtocolInterfaceOptions{}); err == nil {
+		t.Error("missing package name should be rejected")
+	}
+}
+
+func TestGenerateProtocolInterfaceUnsupported(t *testing.T) {
+	proto := Objc_allocateProtocol("UnsupportedPointersProto")
+	Protocol_addMethodDescription(proto, Sel_registerName("count"), "q@:", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("fill:with:"), "v@:^v(U=if)", true, true)
+	Protocol_addMethodDescription(proto, Sel_registerName("link:with:"), "v@:{Node=^v}(U=if)", true, true)
+	Objc_registerProtocol(proto)
+
+	src, err := GenerateProtocolInterface(proto, ProtocolInterfaceOptions{Package: "pointers"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(src), "unsafe") || strings.Contains(string(src), "type Node") {
+		t.Errorf("types of unsupported methods should not be generated:\n%s", src)
+	}
+
+	if err = checkGeneratedSource(src); err != nil {
+		t.Errorf("generated source should type-check: %v\n%s", err, src)
+	}
+}
+
+// checkGeneratedSource type-checks src against the objc declarations used
+// by generated files.
+func checkGeneratedSource(src []byte) error {
+	const objcSrc = `package objc
+
+type Id *struct{}
+type Class *struct{}
+type Sel *struct{}
+type Protocol *struct{}
+
+func Objc_getProtocol(name string) Protocol { return nil }
+
+func RegisterImplementation(name string, superclass Class, impl interface{}, protocols ...Protocol) (Class, error) {
+	return nil, nil
+}
+`
+	fset := token.NewFileSet()
+	objcFile, err := parser.ParseFile(fset, "objc.go", objcSrc, 0)
+
+	if err != nil {
+		return err
+	}
+
+	objcPkg, err := (&types.Config{}).Check(reflect.TypeOf(Id(nil)).PkgPath(), fset, []*ast.File{objcFile}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	file, err := parser.ParseFile(fset, "generated.go", src, 0)
+
+	if err != nil {
+		return err
+	}
+
+	config := &types.Config{Importer: generatedImporter{objcPkg}}
+	_, err = config.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	return err
+}
+
+type generatedImporter struct {
+	objc *types.Package
+}
+
+func (imp generatedImporter) Import(path string) (*types.Package, error) {
+	switch path {
+	case "unsafe":
+		return types.Unsafe, nil
+
+	case imp.objc.Path():
+		return imp.objc, nil
+	}
+
+	return nil, fmt.Errorf("cannot import %s", path)
+}
@@ -0,0 +1,79 @@
+package objc
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// Send sends sel to obj and returns the result as an R. It fails when R
+// does not match the return type of the method or when the number of
+// arguments differs from the method's.
+func Send[R any](obj Id, sel Sel, args ...interface{}) (R, error) {
+	var ret R
+
+	if obj == nil {
+		return ret, nil
+	}
+
+	method := Class_getInstanceMethod(Object_getClass(obj), sel)
+
+	if method == nil {
+		return ret, fmt.Errorf("objc: %s does not respond to %s", Object_getClassName(obj), Sel_getName(sel))
+	}
+
+	if count := Method_getNumberOfArguments(method) - 2; uint(len(args)) != count {
+		return ret, fmt.Errorf("objc: %s takes %d arguments: %d given", Sel_getName(sel), count, len(args))
+	}
+
+	returnType := Method_copyReturnType(method)
+	retType := reflect.TypeOf(&ret).Elem()
+
+	if err := checkReturnType(returnType, retType); err != nil {
+		return ret, fmt.Errorf("objc: %s returns %s: %v", Sel_getName(sel), returnType, err)
+	}
+
+	sig, err := newSignature(Method_getTypeEncoding(method))
+
+	if err != nil {
+		return ret, err
+	}
+
+	args = append([]interface{}{obj, sel}, args...)
+
+	if retType.Kind() == reflect.Interface {
+		value, err := sig.call(msgSendFn(sig), args)
+
+		if value != nil {
+			ret = value.(R)
+		}
+
+		return ret, err
+	}
+
+	if sig.ret.Kind == encoding.Void {
+		_, err = sig.call(msgSendFn(sig), args)
+		return ret, err
+	}
+
+	return ret, sig.callInto(&ret, msgSendFn(sig), args)
+}
+
+// SendClass sends sel to cls and returns the result as an R, like Send.
+func SendClass[R any](cls Class, sel Sel, args ...interface{}) (R, error) {
+	return Send[R](Id(unsafe.Pointer(cls)), sel, args...)
+}
+
+// checkReturnType reports whether values of the encoded type returnType can
+// be returned as t.
+func checkReturnType(returnType string, t reflect.Type) error {
+	ret, err := encoding.Parse(returnType)
+
+	if err != nil {
+		return err
+	}
+
+	return checkType(ret, t)
+}
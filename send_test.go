@@ -0,0 +1,133 @@
+package objc
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestSend(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	class, err := Send[Class](instance, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if class != nsObject {
+		t.Errorf("class should be %p: %p", nsObject, class)
+	}
+}
+
+func TestSendBool(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	responds, err := Send[bool](instance, Sel_registerName("respondsToSelector:"), Sel_registerName("hash"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !responds {
+		t.Error("instance should respond to hash")
+	}
+}
+
+func TestSendAny(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	self, err := Send[interface{}](instance, Sel_registerName("self"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if self != instance {
+		t.Errorf("self should be %p: %v", instance, self)
+	}
+}
+
+func TestSendClass(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+
+	instance, err := SendClass[Id](nsObject, Sel_registerName("new"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if class := Object_getClass(instance); class != nsObject {
+		t.Errorf("instance class should be %p: %p", nsObject, class)
+	}
+}
+
+func TestSendBadReturnType(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Send[float64](instance, Sel_registerName("class")); err == nil {
+		t.Error("sending class as float64 should have failed")
+	}
+}
+
+func TestSendBadArgumentCount(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	instance := Class_createInstance(nsObject, 0)
+
+	if _, err := Send[bool](instance, Sel_registerName("isEqual:"), instance, instance); err == nil {
+		t.Error("sending isEqual: with 2 arguments should have failed")
+	}
+}
+
+func TestSendNil(t *testing.T) {
+	class, err := Send[Class](nil, Sel_registerName("class"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if class != nil {
+		t.Errorf("class should be nil: %p", class)
+	}
+}
+
+func TestCheckReturnType(t *testing.T) {
+	tests := []struct {
+		returnType string
+		value      interface{}
+		valid      bool
+	}{
+		{"c", false, true},
+		{"c", int8(0), true},
+		{"c", int32(0), false},
+		{"q", int64(0), true},
+		{"Q", uint64(0), true},
+		{"i", int64(0), false},
+		{"d", float64(0), true},
+		{"d", float32(0), false},
+		{"@", Id(nil), true},
+		{"@", Class(nil), false},
+		{"*", "", true},
+		{"^v", unsafe.Pointer(nil), true},
+		{"{CGPoint=dd}", struct{ X, Y float64 }{}, true},
+		{"{CGPoint=dd}", struct{ X, Y float32 }{}, false},
+		{"v", struct{}{}, true},
+		{"v", 0, false},
+	}
+
+	for _, test := range tests {
+		err := checkReturnType(test.returnType, reflect.TypeOf(test.value))
+
+		if test.valid && err != nil {
+			t.Errorf("%T should be a valid %s return type: %v", test.value, test.returnType, err)
+		}
+
+		if !test.valid && err == nil {
+			t.Errorf("%T should not be a valid %s return type", test.value, test.returnType)
+		}
+	}
+}
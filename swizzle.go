@@ -0,0 +1,61 @@
+package objc
+
+import (
+	"fmt"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// CheckCompatibility reports whether the implementations of m1 and m2 can
+// be called through one another, and returns an error describing the
+// mismatch otherwise.
+func CheckCompatibility(m1 Method, m2 Method) error {
+	return CheckMethodTypes(m1, Method_getTypeEncoding(m2))
+}
+
+// CheckMethodTypes reports whether the implementation of method can be
+// called with the method type encoding types, such as "v@:i".
+func CheckMethodTypes(method Method, types string) error {
+	sig, err := Method_getSignature(method)
+
+	if err != nil {
+		return err
+	}
+
+	other, err := encoding.ParseMethodSignature(types)
+
+	if err != nil {
+		return err
+	}
+
+	if err = sig.Compatible(other); err != nil {
+		return fmt.Errorf("objc: %s cannot be called as %s: %v", Sel_getName(Method_getName(method)), other, err)
+	}
+
+	return nil
+}
+
+// ExchangeImplementations exchanges the implementations of m1 and m2 like
+// Method_exchangeImplementations, after checking that their signatures are
+// compatible.
+func ExchangeImplementations(m1 Method, m2 Method) error {
+	if err := CheckCompatibility(m1, m2); err != nil {
+		return err
+	}
+
+	Method_exchangeImplementations(m1, m2)
+	return nil
+}
+
+// ReplaceMethod replaces the implementation of the method name of cls like
+// Class_replaceMethod, after checking that types is compatible with the
+// method cls inherits or already implements.
+func ReplaceMethod(cls Class, name Sel, imp Imp, types string) (Imp, error) {
+	if method := Class_getInstanceMethod(cls, name); method != nil {
+		if err := CheckMethodTypes(method, types); err != nil {
+			return nil, err
+		}
+	}
+
+	return Class_replaceMethod(cls, name, imp, types), nil
+}
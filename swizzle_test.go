@@ -0,0 +1,88 @@
+package objc
+
+import "testing"
+
+func TestCheckCompatibility(t *testing.T) {
+	nsObject := Objc_getClass("NSObject")
+	isEqual := Class_getInstanceMethod(nsObject, Sel_registerName("isEqual:"))
+	isKindOfClass := Class_getInstanceMethod(nsObject, Sel_registerName("isKindOfClass:"))
+	hash := Class_getInstanceMethod(nsObject, Sel_registerName("hash"))
+
+	if err := CheckCompatibility(isEqual, isKindOfClass); err != nil {
+		t.Error(err)
+	}
+
+	if err := CheckCompatibility(isEqual, hash); err == nil {
+		t.Error("isEqual: and hash should not be compatible")
+	}
+}
+
+func TestCheckMethodTypes(t *testing.T) {
+	description := Class_getInstanceMethod(Objc_getClass("NSObject"), Sel_registerName("description"))
+
+	if err := CheckMethodTypes(description, "@@:"); err != nil {
+		t.Error(err)
+	}
+
+	if err := CheckMethodTypes(description, "v@:i"); err == nil {
+		t.Error("description should not be callable as v@:i")
+	}
+
+	if err := CheckMethodTypes(description, "@@:{"); err == nil {
+		t.Error("checking a bad encoding should have failed")
+	}
+}
+
+func TestExchangeImplementations(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassForSafeExchange", 0)
+	a := Sel_registerName("a")
+	b := Sel_registerName("b")
+	c := Sel_registerName("c")
+	Class_addGoMethod(class, a, func(self Id, cmd Sel) int32 { return 1 })
+	Class_addGoMethod(class, b, func(self Id, cmd Sel) int32 { return 2 })
+	Class_addGoMethod(class, c, func(self Id, cmd Sel) float64 { return 3 })
+	Objc_registerClassPair(class)
+
+	ma := Class_getInstanceMethod(class, a)
+	mb := Class_getInstanceMethod(class, b)
+	mc := Class_getInstanceMethod(class, c)
+	impA := Method_getImplementation(ma)
+
+	if err := ExchangeImplementations(ma, mb); err != nil {
+		t.Fatal(err)
+	}
+
+	if imp := Method_getImplementation(mb); imp != impA {
+		t.Error("b should be implemented by a")
+	}
+
+	if err := ExchangeImplementations(ma, mc); err == nil {
+		t.Error("exchanging int and double methods should have failed")
+	}
+
+	if imp := Method_getImplementation(mc); imp == impA {
+		t.Error("c should not have been exchanged")
+	}
+}
+
+func TestReplaceMethod(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassForSafeReplace", 0)
+	sel := Sel_registerName("hash")
+	Objc_registerClassPair(class)
+
+	if _, err := ReplaceMethod(class, sel, newTestImp(t, func(self Id, cmd Sel) {}, "v@:"), "v@:"); err == nil {
+		t.Error("replacing hash with a void method should have failed")
+	}
+
+	fn := func(self Id, cmd Sel) uint { return 42 }
+	types, _ := MethodTypeEncoding(fn)
+	imp := newTestImp(t, fn, types)
+
+	if _, err := ReplaceMethod(class, sel, imp, types); err != nil {
+		t.Fatal(err)
+	}
+
+	if ret, _ := Send[uint](Class_createInstance(class, 0), sel); ret != 42 {
+		t.Errorf("hash should be 42: %d", ret)
+	}
+}
@@ -0,0 +1,281 @@
+package objc
+
+// #include <objc/runtime.h>
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/maxence-charriere/go-objcruntime/encoding"
+)
+
+// synthesizedProperty implements the accessors of a property backed by an
+// instance variable.
+type synthesizedProperty struct {
+	cls    Class
+	attrs  PropertyAttributes
+	typ    reflect.Type
+	object bool
+	once   sync.Once
+	offset uintptr
+}
+
+// propertyLocks serialize atomic accessors. They are striped by instance
+// variable address, as in the Objective-C runtime, so that unrelated
+// instances rarely contend.
+var propertyLocks [64]sync.Mutex
+
+// SynthesizeProperty adds the property name to cls, like @synthesize: the
+// backing instance variable, named attrs.Ivar or _name, and Go
+// implementations of the getter and, unless the property is read-only, of
+// the setter. Custom getter and setter names are honored.
+//
+// Object setters retain or copy the new value and release the old one when
+// attrs say so, and weak properties use objc_storeWeak and objc_loadWeak.
+// Atomic accessors are serialized, and atomic getters of retained or copied
+// objects return retained and autoreleased values, like objc_getProperty,
+// so that a concurrent setter cannot deallocate them. As with manual
+// reference counting, the value is not released when the instance is
+// deallocated.
+//
+// cls must not be registered yet since instance variables cannot be added
+// to registered classes. The instance variable and accessors are left in
+// place when adding the property metadata fails.
+func SynthesizeProperty(cls Class, name string, attrs PropertyAttributes) error {
+	_, err := synthesizeProperty(cls, name, attrs)
+	return err
+}
+
+// synthesizeProperty synthesizes the property name and returns the
+// implementations added to cls, even on failure, so they can be freed once
+// cls is disposed. Nothing is added when the property conflicts with a
+// definition of cls.
+func synthesizeProperty(cls Class, name string, attrs PropertyAttributes) ([]Imp, error) {
+	if name == "" {
+		return nil, fmt.Errorf("objc: cannot synthesize a property without name")
+	}
+
+	t, err := encoding.Parse(attrs.Type)
+
+	if err != nil {
+		return nil, fmt.Errorf("objc: cannot synthesize %s: %v", name, err)
+	}
+
+	switch t.Kind {
+	case encoding.Void, encoding.CString, encoding.Array, encoding.Bitfield, encoding.Union:
+		return nil, fmt.Errorf("objc: cannot synthesize %s: unsupported type %s", name, attrs.Type)
+	}
+
+	object := t.Kind == encoding.Object || t.Kind == encoding.Block
+
+	if !object && (attrs.Retain || attrs.Copy || attrs.Weak) {
+		return nil, fmt.Errorf("objc: cannot synthesize %s: retain, copy and weak need an object type", name)
+	}
+
+	if attrs.Ivar == "" {
+		attrs.Ivar = "_" + name
+	}
+
+	getterName := attrs.Getter
+	setterName := attrs.Setter
+
+	if getterName == "" {
+		getterName = name
+	}
+
+	if setterName == "" {
+		setterName = "set" + strings.ToUpper(name[:1]) + name[1:] + ":"
+	}
+
+	if err = checkSynthesizable(cls, name, attrs, getterName, setterName); err != nil {
+		return nil, err
+	}
+
+	prop := &synthesizedProperty{
+		cls:    cls,
+		attrs:  attrs,
+		typ:    goType(t),
+		object: object,
+	}
+
+	getter, err := NewImp(prop.getter().Interface(), attrs.Type+"@:")
+
+	if err != nil {
+		return nil, fmt.Errorf("objc: cannot synthesize %s: %v", name, err)
+	}
+
+	var setter Imp
+
+	if !attrs.ReadOnly {
+		if setter, err = NewImp(prop.setter().Interface(), "v@:"+attrs.Type); err != nil {
+			FreeImp(getter)
+			return nil, fmt.Errorf("objc: cannot synthesize %s: %v", name, err)
+		}
+	}
+
+	freeImps := func() {
+		FreeImp(getter)
+
+		if setter != nil {
+			FreeImp(setter)
+		}
+	}
+
+	if !Class_addIvarWithType(cls, attrs.Ivar, attrs.Type) {
+		freeImps()
+		return nil, fmt.Errorf("objc: cannot add %s to %s", attrs.Ivar, Class_getName(cls))
+	}
+
+	if !Class_addMethod(cls, Sel_registerName(getterName), getter, attrs.Type+"@:") {
+		freeImps()
+		return nil, fmt.Errorf("objc: cannot add %s to %s", getterName, Class_getName(cls))
+	}
+
+	imps := []Imp{getter}
+
+	if setter != nil {
+		if !Class_addMethod(cls, Sel_registerName(setterName), setter, "v@:"+attrs.Type) {
+			FreeImp(setter)
+			return imps, fmt.Errorf("objc: cannot add %s to %s", setterName, Class_getName(cls))
+		}
+
+		imps = append(imps, setter)
+	}
+
+	if !Class_addProperty(cls, name, attrs.List()) {
+		return imps, fmt.Errorf("objc: cannot add property %s to %s", name, Class_getName(cls))
+	}
+
+	return imps, nil
+}
+
+// checkSynthesizable checks that cls does not already define the instance
+// variable, accessors or property synthesized for name.
+func checkSynthesizable(cls Class, name string, attrs PropertyAttributes, getterName string, setterName string) error {
+	if Class_getInstanceVariable(cls, attrs.Ivar) != nil {
+		return fmt.Errorf("objc: cannot synthesize %s: %s already has %s", name, Class_getName(cls), attrs.Ivar)
+	}
+
+	accessors := map[string]bool{getterName: true}
+
+	if !attrs.ReadOnly {
+		accessors[setterName] = true
+	}
+
+	for _, method := range Class_copyMethodList(cls) {
+		if selector := Sel_getName(Method_getName(method)); accessors[selector] {
+			return fmt.Errorf("objc: cannot synthesize %s: %s already has %s", name, Class_getName(cls), selector)
+		}
+	}
+
+	for _, property := range Class_copyPropertyList(cls) {
+		if Property_getName(property) == name {
+			return fmt.Errorf("objc: cannot synthesize %s: %s already has the property", name, Class_getName(cls))
+		}
+	}
+
+	return nil
+}
+
+// ivar returns the address of the instance variable of self. The offset is
+// looked up on first use, once the class is registered.
+func (prop *synthesizedProperty) ivar(self Id) unsafe.Pointer {
+	prop.once.Do(func() {
+		prop.offset = uintptr(Ivar_getOffset(Class_getInstanceVariable(prop.cls, prop.attrs.Ivar)))
+	})
+
+	return unsafe.Add(unsafe.Pointer(self), prop.offset)
+}
+
+// lock locks the instance variable at ptr unless the property is
+// nonatomic, and returns the function unlocking it.
+func (prop *synthesizedProperty) lock(ptr unsafe.Pointer) func() {
+	if prop.attrs.Nonatomic {
+		return func() {}
+	}
+
+	addr := uintptr(ptr)
+	mutex := &propertyLocks[((addr>>4)^(addr>>9))%uintptr(len(propertyLocks))]
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+func (prop *synthesizedProperty) getter() reflect.Value {
+	fnType := reflect.FuncOf([]reflect.Type{idType, selType}, []reflect.Type{prop.typ}, false)
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		ptr := prop.ivar(args[0].Interface().(Id))
+
+		if prop.attrs.Weak {
+			return []reflect.Value{reflect.ValueOf(Id(C.objc_loadWeak((*C.id)(ptr))))}
+		}
+
+		// Like objc_getProperty, atomic getters retain the value while the
+		// setter cannot release it, and autorelease it for the caller.
+		if prop.object && !prop.attrs.Nonatomic && (prop.attrs.Retain || prop.attrs.Copy) {
+			unlock := prop.lock(ptr)
+			value := sendObject(*(*Id)(ptr), "retain")
+			unlock()
+
+			return []reflect.Value{reflect.ValueOf(sendObject(value, "autorelease")).Convert(prop.typ)}
+		}
+
+		defer prop.lock(ptr)()
+
+		value := reflect.New(prop.typ).Elem()
+		value.Set(reflect.NewAt(prop.typ, ptr).Elem())
+		return []reflect.Value{value}
+	})
+}
+
+func (prop *synthesizedProperty) setter() reflect.Value {
+	fnType := reflect.FuncOf([]reflect.Type{idType, selType, prop.typ}, nil, false)
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		ptr := prop.ivar(args[0].Interface().(Id))
+
+		if !prop.object {
+			defer prop.lock(ptr)()
+			reflect.NewAt(prop.typ, ptr).Elem().Set(args[2])
+			return nil
+		}
+
+		value := args[2].Interface().(Id)
+
+		switch {
+		case prop.attrs.Weak:
+			C.objc_storeWeak((*C.id)(ptr), C.id(value))
+			return nil
+
+		case prop.attrs.Copy:
+			value = sendObject(value, "copy")
+
+		case prop.attrs.Retain:
+			value = sendObject(value, "retain")
+		}
+
+		unlock := prop.lock(ptr)
+		old := *(*Id)(ptr)
+		*(*Id)(ptr) = value
+		unlock()
+
+		if prop.attrs.Copy || prop.attrs.Retain {
+			sendObject(old, "release")
+		}
+
+		return nil
+	})
+}
+
+func sendObject(obj Id, name string) Id {
+	ret, _ := Objc_msgSend(obj, Sel_registerName(name))
+
+	if obj, ok := ret.(Id); ok {
+		return obj
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package objc
+
+import "testing"
+
+func TestSynthesizeProperty(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithSynthesizedProperties", 0)
+
+	properties := []struct {
+		name  string
+		attrs PropertyAttributes
+	}{
+		{"count", PropertyAttributes{Type: "i", Nonatomic: true}},
+		{"origin", PropertyAttributes{Type: "{CGPoint=dd}"}},
+		{"owner", PropertyAttributes{Type: "@", Retain: true, Nonatomic: true}},
+		{"enabled", PropertyAttributes{Type: "B", Getter: "isEnabled"}},
+		{"identifier", PropertyAttributes{Type: "q", ReadOnly: true, Ivar: "identifierStorage"}},
+	}
+
+	for _, property := range properties {
+		if err := SynthesizeProperty(class, property.name, property.attrs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	Objc_registerClassPair(class)
+	obj := Class_createInstance(class, 0)
+
+	if _, err := Objc_msgSend(obj, Sel_registerName("setCount:"), int32(42)); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, _ := Send[int32](obj, Sel_registerName("count")); count != 42 {
+		t.Errorf("count should be 42: %d", count)
+	}
+
+	type point struct{ X, Y float64 }
+
+	Objc_msgSend(obj, Sel_registerName("setOrigin:"), point{1, 2})
+
+	if origin, _ := Send[point](obj, Sel_registerName("origin")); origin != (point{1, 2}) {
+		t.Errorf("origin should be {1 2}: %v", origin)
+	}
+
+	owner := Class_createInstance(Objc_getClass("NSObject"), 0)
+	Objc_msgSend(obj, Sel_registerName("setOwner:"), owner)
+
+	if ret, _ := Send[Id](obj, Sel_registerName("owner")); ret != owner {
+		t.Error("owner should be set")
+	}
+
+	if retainCount, _ := Send[uint](owner, Sel_registerName("retainCount")); retainCount != 2 {
+		t.Errorf("owner should be retained: %d", retainCount)
+	}
+
+	Objc_msgSend(obj, Sel_registerName("setOwner:"), nil)
+
+	if retainCount, _ := Send[uint](owner, Sel_registerName("retainCount")); retainCount != 1 {
+		t.Errorf("owner should be released: %d", retainCount)
+	}
+
+	Objc_msgSend(obj, Sel_registerName("setEnabled:"), true)
+
+	if enabled, _ := Send[bool](obj, Sel_registerName("isEnabled")); !enabled {
+		t.Error("enabled should be true")
+	}
+
+	if Class_getInstanceMethod(class, Sel_registerName("setIdentifier:")) != nil {
+		t.Error("identifier should not have a setter")
+	}
+
+	if Class_getInstanceVariable(class, "identifierStorage") == nil {
+		t.Error("identifier should be stored in identifierStorage")
+	}
+
+	if attributes := Property_getAttributes(Class_getProperty(class, "owner")); attributes != "T@,&,N,V_owner" {
+		t.Errorf("owner attributes should be T@,&,N,V_owner: %s", attributes)
+	}
+}
+
+func TestSynthesizePropertyAtomicGetter(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithAtomicProperty", 0)
+
+	if err := SynthesizeProperty(class, "parent", PropertyAttributes{Type: "@", Retain: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	Objc_registerClassPair(class)
+	obj := Class_createInstance(class, 0)
+	parent := Class_createInstance(Objc_getClass("NSObject"), 0)
+	Objc_msgSend(obj, Sel_registerName("setParent:"), parent)
+
+	if ret, _ := Send[Id](obj, Sel_registerName("parent")); ret != parent {
+		t.Error("parent should be set")
+	}
+
+	if retainCount, _ := Send[uint](parent, Sel_registerName("retainCount")); retainCount != 3 {
+		t.Errorf("parent should be retained and autoreleased by the getter: %d", retainCount)
+	}
+}
+
+func TestSynthesizePropertyInvalid(t *testing.T) {
+	class := Objc_allocateClassPair(Objc_getClass("NSObject"), "ClassWithInvalidProperties", 0)
+
+	tests := []struct {
+		name  string
+		attrs PropertyAttributes
+	}{
+		{"", PropertyAttributes{Type: "i"}},
+		{"untyped", PropertyAttributes{}},
+		{"name", PropertyAttributes{Type: "*"}},
+		{"values", PropertyAttributes{Type: "[4i]"}},
+		{"count", PropertyAttributes{Type: "i", Copy: true}},
+	}
+
+	for _, test := range tests {
+		if err := SynthesizeProperty(class, test.name, test.attrs); err == nil {
+			t.Errorf("synthesizing %q should have failed", test.name)
+		}
+	}
+
+	SynthesizeProperty(class, "count", PropertyAttributes{Type: "i"})
+
+	if err := SynthesizeProperty(class, "count", PropertyAttributes{Type: "i"}); err == nil {
+		t.Error("synthesizing count twice should have failed")
+	}
+
+	if err := SynthesizeProperty(class, "total", PropertyAttributes{Type: "i", Getter: "count"}); err == nil {
+		t.Error("synthesizing total with the getter count should have failed")
+	}
+
+	if Class_getInstanceVariable(class, "_total") != nil {
+		t.Error("total should not have been partially synthesized")
+	}
+}